@@ -14,18 +14,42 @@ import (
 type Adder struct {
 	config    *Config
 	generator *Generator
+	output    OutputFS // Set by WithOutputFS, nil for the output directory
+}
+
+// Option configures an Adder
+type Option func(*Adder)
+
+// WithEmitter registers emitters that write additional files from the command tree on every generation
+func WithEmitter(emitters ...Emitter) Option {
+	return func(a *Adder) {
+		a.generator.AddEmitter(emitters...)
+	}
+}
+
+// WithOutputFS writes generated files to fsys instead of the output directory
+// Use NewMemoryFS to generate without touching disk
+func WithOutputFS(fsys OutputFS) Option {
+	return func(a *Adder) {
+		a.output = fsys
+		a.generator.SetOutputFS(fsys)
+	}
 }
 
 // New creates a new Adder instance with the given configuration
-func New(config *Config) *Adder {
+func New(config *Config, opts ...Option) *Adder {
 	if config == nil {
 		config = DefaultConfig()
 	}
 
-	return &Adder{
+	a := &Adder{
 		config:    config,
 		generator: NewGenerator(config),
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 // NewWithDefaults creates a new Adder instance with default configuration
@@ -45,7 +69,8 @@ func (a *Adder) GenerateWithContext(ctx context.Context) error {
 
 // GenerateOptions holds options for code generation
 type GenerateOptions struct {
-	Force bool // Force regeneration of all files
+	Force     bool // Force regeneration of all files
+	KeepStale bool // Keep generated files whose markdown source no longer exists
 }
 
 // GenerateWithOptions processes the input directory and generates command stubs with options
@@ -57,6 +82,7 @@ func (a *Adder) GenerateWithOptions(ctx context.Context, opts GenerateOptions) e
 
 	// Set force regeneration option
 	a.generator.SetForceRegeneration(opts.Force)
+	a.generator.SetKeepStale(opts.KeepStale)
 
 	// Create filesystem from input directory
 	inputFS := os.DirFS(a.config.InputDir)
@@ -69,6 +95,17 @@ func (a *Adder) GenerateWithOptions(ctx context.Context, opts GenerateOptions) e
 	return nil
 }
 
+// Changes returns the pending changes to generated files without writing anything
+// An empty result means the generated code is up to date with the markdown
+func (a *Adder) Changes(ctx context.Context) ([]FileChange, error) {
+	// Check if input directory exists
+	if _, err := os.Stat(a.config.InputDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("input directory does not exist: %s", a.config.InputDir)
+	}
+
+	return a.generator.Changes(ctx, os.DirFS(a.config.InputDir))
+}
+
 // GenerateFromFS generates code from the provided filesystem
 func (a *Adder) GenerateFromFS(ctx context.Context, inputFS fs.FS) error {
 	return a.generator.Generate(ctx, inputFS)
@@ -138,6 +175,31 @@ func (a *Adder) ParseCommands() ([]*Command, error) {
 	return a.generator.parser.ParseDirectory(inputFS)
 }
 
+// CommandTree parses commands from the input directory and arranges them into a tree
+func (a *Adder) CommandTree() (*CommandNode, error) {
+	commands, err := a.ParseCommands()
+	if err != nil {
+		return nil, err
+	}
+	return BuildCommandTree(a.config.BinaryName, commands), nil
+}
+
+// RenderCommand returns the generated Go file for a single command without writing anything
+func (a *Adder) RenderCommand(cmd *Command) ([]byte, error) {
+	return a.generator.RenderCommand(cmd)
+}
+
+// GetRemovedFiles returns the stale files removed by the last generation
+func (a *Adder) GetRemovedFiles() []string {
+	return a.generator.RemovedFiles()
+}
+
+// GetKeptFiles returns the stale emitted files the last generation or clean left in place,
+// because they were modified since they were emitted
+func (a *Adder) GetKeptFiles() []string {
+	return a.generator.KeptFiles()
+}
+
 // GetCommand returns a specific command by name
 func (a *Adder) GetCommand(name string) *Command {
 	return a.generator.GetCommand(name)
@@ -149,9 +211,15 @@ func (a *Adder) GetStats() map[string]int {
 }
 
 // SetConfig updates the configuration
+// Registered emitters and the output FS are kept
 func (a *Adder) SetConfig(config *Config) {
+	emitters := a.generator.emitters
 	a.config = config
 	a.generator = NewGenerator(config)
+	a.generator.AddEmitter(emitters...)
+	if a.output != nil {
+		a.generator.SetOutputFS(a.output)
+	}
 }
 
 // GetConfig returns the current configuration
@@ -159,11 +227,56 @@ func (a *Adder) GetConfig() *Config {
 	return a.config
 }
 
-// Clean removes all generated files
+// CleanOptions holds options for removing generated files
+type CleanOptions struct {
+	All    bool // Remove every generated file, not only those without a markdown source
+	DryRun bool // Report the files without removing them
+}
+
+// Clean removes all generated files from the output directory
+// Only files starting with the "Code generated by adder" header are removed
 func (a *Adder) Clean() error {
-	// This would remove files matching the pattern in the output directory
-	// For now, just return nil as it's not implemented
-	return nil
+	_, err := a.CleanWithOptions(context.Background(), CleanOptions{All: true})
+	return err
+}
+
+// CleanWithOptions removes generated files and returns their paths
+// By default only stale files are removed: outputs of markdown files that were deleted or renamed
+func (a *Adder) CleanWithOptions(ctx context.Context, opts CleanOptions) ([]string, error) {
+	var files []string
+	var err error
+	if opts.All {
+		files, err = a.generator.generatedFiles()
+	} else {
+		// Check if input directory exists
+		if _, statErr := os.Stat(a.config.InputDir); os.IsNotExist(statErr) {
+			return nil, fmt.Errorf("input directory does not exist: %s", a.config.InputDir)
+		}
+		files, err = a.generator.StaleFiles(ctx, os.DirFS(a.config.InputDir))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding generated files: %w", err)
+	}
+
+	// Without generated files the lockfile has nothing left to describe
+	if opts.All {
+		// Emitted Go files are removed only if unmodified, like every emitted file
+		previous := a.generator.readLock()
+		emittedStale, kept, _ := a.generator.staleEmitted(previous, nil)
+		files = append(a.generator.withoutEmitted(files, previous, nil), emittedStale...)
+		a.generator.keptFiles = kept
+		if _, err := fs.Stat(a.generator.output, LockFileName); err == nil {
+			files = append(files, a.generator.lockPath())
+		}
+	}
+
+	if !opts.DryRun {
+		if err := a.generator.removeFiles(files); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
 }
 
 // Version returns the version of the adder package
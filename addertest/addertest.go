@@ -0,0 +1,102 @@
+// Package addertest provides helpers for testing CLIs built from adder generated commands.
+//
+// Run executes a command tree with string arguments and captures its output,
+// Recorder and ResultRecorder are fake handlers capturing the typed requests the
+// CLI produced, and Golden compares output against files in testdata.
+package addertest
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jrschumacher/adder"
+	"github.com/spf13/cobra"
+)
+
+// Result is the outcome of executing a command
+type Result struct {
+	Stdout   string
+	Stderr   string
+	Err      error // error returned by the command, if any
+	ExitCode int   // exit code adder.Execute would have returned
+}
+
+// Run executes the root command of cmd with args and captures its output
+// Output written to os.Stdout and os.Stderr (e.g. with fmt.Println) is captured too,
+// so tests using Run must not run in parallel
+func Run(t testing.TB, cmd *cobra.Command, args ...string) *Result {
+	t.Helper()
+	return RunWithInput(t, cmd, "", args...)
+}
+
+// RunWithInput is like Run and provides input as the command's stdin
+func RunWithInput(t testing.TB, cmd *cobra.Command, input string, args ...string) *Result {
+	t.Helper()
+
+	root := cmd.Root()
+	stdout := capture(t, &os.Stdout)
+	stderr := capture(t, &os.Stderr)
+
+	root.SetArgs(args)
+	root.SetIn(strings.NewReader(input))
+	root.SetOut(stdout.writer)
+	root.SetErr(stderr.writer)
+	defer func() {
+		root.SetArgs(nil)
+		root.SetIn(nil)
+		root.SetOut(nil)
+		root.SetErr(nil)
+	}()
+
+	err := adder.ExecuteE(root)
+
+	return &Result{
+		Stdout:   stdout.close(),
+		Stderr:   stderr.close(),
+		Err:      err,
+		ExitCode: adder.ExitCode(err),
+	}
+}
+
+// capturedStream redirects an *os.File variable to a pipe while a command runs
+type capturedStream struct {
+	target   **os.File
+	original *os.File
+	writer   *os.File
+	buf      bytes.Buffer
+	done     sync.WaitGroup
+}
+
+// capture redirects target (os.Stdout or os.Stderr) until close is called
+func capture(t testing.TB, target **os.File) *capturedStream {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("capturing output: %v", err)
+	}
+
+	s := &capturedStream{target: target, original: *target, writer: writer}
+	*target = writer
+
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		_, _ = io.Copy(&s.buf, reader)
+		_ = reader.Close()
+	}()
+
+	return s
+}
+
+// close restores the original stream and returns the captured output
+func (s *capturedStream) close() string {
+	*s.target = s.original
+	_ = s.writer.Close()
+	s.done.Wait()
+	return s.buf.String()
+}
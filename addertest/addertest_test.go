@@ -0,0 +1,141 @@
+package addertest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+func TestRun(t *testing.T) {
+	root := &cobra.Command{Use: "app", SilenceUsage: true}
+	root.AddCommand(&cobra.Command{
+		Use: "hello",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println("printed to os.Stdout")
+			fmt.Fprintln(cmd.OutOrStdout(), "printed to cmd.OutOrStdout")
+			fmt.Fprintln(os.Stderr, "printed to os.Stderr")
+			return nil
+		},
+	})
+	root.AddCommand(&cobra.Command{
+		Use: "fail",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return adder.Errorf(7, "quota exceeded")
+		},
+	})
+
+	result := Run(t, root, "hello")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if result.Stdout != "printed to os.Stdout\nprinted to cmd.OutOrStdout\n" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+	if result.Stderr != "printed to os.Stderr\n" {
+		t.Errorf("Stderr = %q", result.Stderr)
+	}
+
+	result = Run(t, root, "fail")
+	if result.ExitCode != 7 {
+		t.Errorf("ExitCode = %d, want 7", result.ExitCode)
+	}
+	if !strings.Contains(result.Stderr, "Error: quota exceeded") {
+		t.Errorf("Stderr = %q, want cobra error message", result.Stderr)
+	}
+
+	// Flag errors are usage errors, as with adder.Execute
+	result = Run(t, root, "hello", "--nope")
+	var usageErr *adder.UsageError
+	if result.ExitCode != adder.ExitCodeUsage || !errors.As(result.Err, &usageErr) {
+		t.Errorf("ExitCode = %d, Err = %v, want a usage error", result.ExitCode, result.Err)
+	}
+
+	result = Run(t, root, "nope")
+	if result.ExitCode != adder.ExitCodeUsage {
+		t.Errorf("ExitCode = %d, want usage error", result.ExitCode)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := &Recorder[generated.GenerateRequest]{}
+	root := generated.NewAdderCommand(func(cmd *cobra.Command, req *generated.AdderRequest) error { return nil })
+	root.AddCommand(generated.NewGenerateCommand(rec.Handle))
+
+	result := Run(t, root, "generate", "--binary-name", "mycli", "-p", "commands", "--force")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+
+	if rec.Calls() != 1 {
+		t.Fatalf("Calls() = %d, want 1", rec.Calls())
+	}
+	req := rec.Last()
+	if req.Flags.BinaryName != "mycli" || req.Flags.Package != "commands" || !req.Flags.Force {
+		t.Errorf("unexpected request flags: %+v", req.Flags)
+	}
+	if req.Flags.Input != "docs/commands" {
+		t.Errorf("Input = %q, want declared default", req.Flags.Input)
+	}
+
+	// Errors set on the recorder are returned to the CLI
+	rec.Err = errors.New("boom")
+	if result := Run(t, root, "generate", "-b", "mycli"); result.ExitCode != adder.ExitCodeError {
+		t.Errorf("ExitCode = %d, want %d", result.ExitCode, adder.ExitCodeError)
+	}
+	if len(rec.Requests()) != 2 {
+		t.Errorf("Requests() = %d, want 2", len(rec.Requests()))
+	}
+}
+
+func TestResultRecorder(t *testing.T) {
+	type listRequest struct{ Filter string }
+	type listResponse struct{ Name string }
+
+	rec := &ResultRecorder[listRequest, []listResponse]{Result: []listResponse{{Name: "web"}}}
+	handler := func(cmd *cobra.Command, req *listRequest) ([]listResponse, error) { return rec.Handle(cmd, req) }
+
+	result, err := handler(nil, &listRequest{Filter: "w*"})
+	if err != nil || len(result) != 1 || result[0].Name != "web" {
+		t.Errorf("Handle() = %v, %v", result, err)
+	}
+	if rec.Last().Filter != "w*" {
+		t.Errorf("Last() = %+v", rec.Last())
+	}
+}
+
+// update is the -update flag every test package using golden files declares
+var update = flag.Bool(UpdateFlag, false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	root := &cobra.Command{
+		Use: "app",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(cmd.OutOrStdout(), "hello %s\n", strings.Join(args, " "))
+		},
+	}
+
+	result := Run(t, root, "golden", "world")
+	GoldenString(t, "hello", result.Stdout, *update)
+
+	// Updating writes the golden file instead of comparing it
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+	GoldenString(t, "new", "content\n", true)
+	if content, err := os.ReadFile(GoldenPath("new")); err != nil || string(content) != "content\n" {
+		t.Errorf("golden file = %q, %v", content, err)
+	}
+}
@@ -0,0 +1,55 @@
+package addertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateFlag is the name of the test flag that rewrites golden files instead of comparing them
+// addertest cannot define it without clashing with test packages that do, so every test package
+// using golden files declares it and passes its value to Golden:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	addertest.GoldenString(t, "greet", res.Stdout, *update)
+//
+//	go test ./... -update
+const UpdateFlag = "update"
+
+// GoldenPath returns the path of the golden file for name
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Golden compares got with the golden file testdata/<name>.golden
+// With update, usually the test package's -update flag, the golden file is written instead
+func Golden(t testing.TB, name string, got []byte, update bool) {
+	t.Helper()
+
+	path := GoldenPath(name)
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -%s to create it): %v", UpdateFlag, err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("output does not match %s (run with -%s to update)\n--- want\n%s\n+++ got\n%s", path, UpdateFlag, want, got)
+	}
+}
+
+// GoldenString is Golden for string output, e.g. Result.Stdout
+func GoldenString(t testing.TB, name, got string, update bool) {
+	t.Helper()
+	Golden(t, name, []byte(got), update)
+}
@@ -0,0 +1,68 @@
+package addertest
+
+import (
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Recorder is a fake handler recording the typed requests a generated command produced
+//
+//	rec := &addertest.Recorder[generated.GreetRequest]{}
+//	root.AddCommand(generated.NewGreetCommand(rec.Handle))
+type Recorder[R any] struct {
+	// Err is returned by Handle
+	Err error
+
+	mu       sync.Mutex
+	requests []*R
+}
+
+// Handle records the request and returns r.Err
+func (r *Recorder[R]) Handle(_ *cobra.Command, req *R) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return r.Err
+}
+
+// Requests returns the recorded requests in call order
+func (r *Recorder[R]) Requests() []*R {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*R(nil), r.requests...)
+}
+
+// Calls returns the number of recorded requests
+func (r *Recorder[R]) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// Last returns the most recent request, or nil if the handler was not called
+func (r *Recorder[R]) Last() *R {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		return nil
+	}
+	return r.requests[len(r.requests)-1]
+}
+
+// ResultRecorder is a Recorder for commands with a declared output, whose handlers return a result
+//
+//	rec := &addertest.ResultRecorder[generated.ListRequest, []generated.ListResponse]{Result: rows}
+//	root.AddCommand(generated.NewListCommand(rec.Handle))
+type ResultRecorder[R any, T any] struct {
+	Recorder[R]
+
+	// Result is returned by Handle
+	Result T
+}
+
+// Handle records the request and returns r.Result and r.Err
+func (r *ResultRecorder[R, T]) Handle(cmd *cobra.Command, req *R) (T, error) {
+	err := r.Recorder.Handle(cmd, req)
+	return r.Result, err
+}
@@ -0,0 +1,173 @@
+package adder
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// generatedHeader marks every file written by adder
+// Files without it are never removed, even inside the output directory
+const generatedHeader = "// Code generated by adder. DO NOT EDIT."
+
+// isGeneratedFile reports whether the leading comments of the file name in fsys contain the adder generated header
+// A custom header template can put comments, e.g. a license, before it
+func isGeneratedFile(fsys fs.FS, name string) (bool, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == generatedHeader {
+			return true, nil
+		}
+		// The header must come before the package clause
+		if line != "" && !strings.HasPrefix(line, "//") {
+			break
+		}
+	}
+	return false, scanner.Err()
+}
+
+// generatedFiles returns the Go files below the output directory that were generated by adder
+// Nested Go modules are skipped
+func (g *Generator) generatedFiles() ([]string, error) {
+	var files []string
+	err := fs.WalkDir(g.output, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if name == "." && errors.Is(err, fs.ErrNotExist) {
+				return nil // nothing generated yet
+			}
+			return err
+		}
+		if d.IsDir() {
+			// Other modules, e.g. an example below the output directory, have their own generated code
+			if name != "." {
+				if _, err := fs.Stat(g.output, path.Join(name, "go.mod")); err == nil {
+					return fs.SkipDir
+				}
+			}
+			return nil
+		}
+		if path.Ext(name) != ".go" {
+			return nil
+		}
+
+		filename := filepath.Join(g.config.OutputDir, filepath.FromSlash(name))
+		generated, err := isGeneratedFile(g.output, name)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", filename, err)
+		}
+		if generated {
+			files = append(files, filename)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// staleFiles returns generated files that no parsed command writes anymore,
+// e.g. after a markdown file was deleted or renamed
+// Emitted files, current ones and those recorded in previous, are left to staleEmitted.
+// Requires the commands to be parsed
+func (g *Generator) staleFiles(previous map[string]lockEntry, emitted []emittedOutput) ([]string, error) {
+	files, err := g.generatedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := g.groupCommandsByFile()
+	var stale []string
+	for _, file := range g.withoutEmitted(files, previous, emitted) {
+		if _, ok := outputs[file]; !ok {
+			stale = append(stale, file)
+		}
+	}
+	return stale, nil
+}
+
+// withoutEmitted drops the files written by emitted or recorded as emitted in previous
+// Emitters may write Go files with the adder header, which are not generated code of a command
+func (g *Generator) withoutEmitted(files []string, previous map[string]lockEntry, emitted []emittedOutput) []string {
+	skip := make(map[string]bool)
+	for _, out := range emitted {
+		skip[filepath.Clean(out.path)] = true
+	}
+	for output, entry := range previous {
+		if entry.Emitter != "" {
+			skip[filepath.Join(g.config.OutputDir, filepath.FromSlash(output))] = true
+		}
+	}
+
+	var kept []string
+	for _, file := range files {
+		if !skip[filepath.Clean(file)] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// StaleFiles parses the input filesystem and returns the generated files without a markdown source
+// Files recorded as emitted that no emitter writes anymore are stale as well, unless they were modified;
+// those are reported by KeptFiles
+func (g *Generator) StaleFiles(ctx context.Context, inputFS fs.FS) ([]string, error) {
+	commands, err := g.parser.ParseDirectoryContext(ctx, inputFS)
+	if err != nil {
+		return nil, fmt.Errorf("parsing directory: %w", err)
+	}
+
+	g.commands = commands
+
+	emitted, err := g.emit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previous := g.readLock()
+	stale, err := g.staleFiles(previous, emitted)
+	if err != nil {
+		return nil, err
+	}
+	emittedStale, kept, _ := g.staleEmitted(previous, emitted)
+	g.keptFiles = kept
+	return append(stale, emittedStale...), nil
+}
+
+// removeFiles deletes generated files, then the directories below the output directory they leave empty
+func (g *Generator) removeFiles(paths []string) error {
+	root := filepath.Clean(g.config.OutputDir)
+
+	for _, path := range paths {
+		if err := g.removeOutput(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing %s: %w", path, err)
+		}
+
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				break
+			}
+			// Removing a directory that still has files fails, which ends the walk up
+			if g.removeOutput(dir) != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package adder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// cleanTestConfig writes markdown for the given commands and returns a config generating them
+func cleanTestConfig(t *testing.T, names ...string) *Config {
+	t.Helper()
+	inputDir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(inputDir, filepath.FromSlash(name)+".md")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		content := "---\ntitle: " + name + "\ncommand:\n  name: " + filepath.Base(name) + "\n---\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := DefaultConfig()
+	config.InputDir = inputDir
+	config.OutputDir = t.TempDir()
+	config.Package = "testpkg"
+	return config
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestGenerator_RemovesStaleFiles(t *testing.T) {
+	config := cleanTestConfig(t, "keep", "users/old")
+	if err := New(config).GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	oldOutput := filepath.Join(config.OutputDir, "users", "old_generated.go")
+	handWritten := filepath.Join(config.OutputDir, "handlers.go")
+	writeTestFile(t, handWritten, "package testpkg\n")
+	nested := filepath.Join(config.OutputDir, "example", "generated", "x_generated.go")
+	writeTestFile(t, filepath.Join(config.OutputDir, "example", "go.mod"), "module example\n")
+	writeTestFile(t, nested, generatedHeader+"\n\npackage generated\n")
+
+	// Rename the markdown source: its old output is stale
+	if err := os.Rename(filepath.Join(config.InputDir, "users", "old.md"), filepath.Join(config.InputDir, "new.md")); err != nil {
+		t.Fatalf("Failed to rename: %v", err)
+	}
+
+	changes, err := New(config).Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	var deleted []string
+	for _, change := range changes {
+		if change.Action == ChangeDelete {
+			deleted = append(deleted, change.Path)
+		}
+	}
+	if !reflect.DeepEqual(deleted, []string{oldOutput}) {
+		t.Errorf("Changes() deletions = %v, want %v", deleted, []string{oldOutput})
+	}
+
+	// Keeping stale files leaves the old output in place
+	generator := New(config)
+	if err := generator.GenerateWithOptions(context.Background(), GenerateOptions{KeepStale: true}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(oldOutput); err != nil {
+		t.Errorf("KeepStale removed %s: %v", oldOutput, err)
+	}
+
+	generator = New(config)
+	if err := generator.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !reflect.DeepEqual(generator.GetRemovedFiles(), []string{oldOutput}) {
+		t.Errorf("GetRemovedFiles() = %v, want %v", generator.GetRemovedFiles(), []string{oldOutput})
+	}
+	if _, err := os.Stat(filepath.Dir(oldOutput)); !os.IsNotExist(err) {
+		t.Error("the emptied users directory should be removed")
+	}
+	for _, path := range []string{handWritten, nested, filepath.Join(config.OutputDir, "new_generated.go")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should be kept: %v", path, err)
+		}
+	}
+}
+
+func TestAdder_CleanWithOptions(t *testing.T) {
+	config := cleanTestConfig(t, "alpha", "beta")
+	if err := New(config).GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	handWritten := filepath.Join(config.OutputDir, "handlers.go")
+	writeTestFile(t, handWritten, "// Code generated by another tool. DO NOT EDIT.\n\npackage testpkg\n")
+
+	alpha := filepath.Join(config.OutputDir, "alpha_generated.go")
+	beta := filepath.Join(config.OutputDir, "beta_generated.go")
+	if err := os.Remove(filepath.Join(config.InputDir, "beta.md")); err != nil {
+		t.Fatalf("Failed to remove markdown: %v", err)
+	}
+
+	files, err := New(config).CleanWithOptions(context.Background(), CleanOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{beta}) {
+		t.Errorf("dry run files = %v, want %v", files, []string{beta})
+	}
+	if _, err := os.Stat(beta); err != nil {
+		t.Errorf("dry run removed %s", beta)
+	}
+
+	files, err = New(config).CleanWithOptions(context.Background(), CleanOptions{})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{beta}) {
+		t.Errorf("stale files = %v, want %v", files, []string{beta})
+	}
+
+	if err := New(config).Clean(); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	lock := filepath.Join(config.OutputDir, LockFileName)
+	for path, wantExists := range map[string]bool{alpha: false, beta: false, lock: false, handWritten: true} {
+		if _, err := os.Stat(path); (err == nil) != wantExists {
+			t.Errorf("%s exists = %v, want %v", path, err == nil, wantExists)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+// cleanCmd removes stale, or with --all every, generated file from the output directory
+func cleanCmd(cmd *cobra.Command, req *generated.CleanRequest) error {
+	// Load config from --config or the nearest config file
+	fileConfig, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	targets, err := fileConfig.SelectTargets(req.Flags.Target)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	for _, target := range targets {
+		// Merge command line flags with config file (flags take precedence)
+		config, err := applyFlags(cmd, target,
+			flagOverride{"input", "input", req.Flags.Input},
+			flagOverride{"output", "output", req.Flags.Output},
+		)
+		if err != nil {
+			return err
+		}
+		if config.Name != "" {
+			fmt.Printf("🎯 Target %s\n", config.Name)
+		}
+		if err := cleanTarget(cmd, config, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanTarget removes the generated files of a single target
+func cleanTarget(cmd *cobra.Command, config *adder.Config, req *generated.CleanRequest) error {
+	generator := adder.New(config)
+	files, err := generator.CleanWithOptions(commandContext(cmd), adder.CleanOptions{
+		All:    req.Flags.All,
+		DryRun: req.Flags.DryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Clean failed: %w", err)
+	}
+
+	printKeptFiles(generator.GetKeptFiles())
+	if len(files) == 0 {
+		fmt.Printf("✅ No generated files to remove in %s\n", config.OutputDir)
+		return nil
+	}
+
+	if req.Flags.DryRun {
+		fmt.Printf("🔍 Would remove %d generated files:\n", len(files))
+	} else {
+		fmt.Printf("🧹 Removed %d generated files:\n", len(files))
+	}
+	for _, file := range files {
+		fmt.Printf("  - %s\n", file)
+	}
+
+	return nil
+}
+
+// printKeptFiles lists stale emitted files that were left in place because they were modified
+func printKeptFiles(kept []string) {
+	if len(kept) == 0 {
+		return
+	}
+	fmt.Printf("⚠️  Kept %d stale emitted files modified since they were generated:\n", len(kept))
+	for _, file := range kept {
+		fmt.Printf("  - %s\n", file)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+// configCmd lists the effective config values of each target and where they came from
+func configCmd(cmd *cobra.Command, req *generated.ConfigRequest) ([]generated.ConfigResponse, error) {
+	// Load config from --config or the nearest config file
+	fileConfig, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := fileConfig.SelectTargets(req.Flags.Target)
+	if err != nil {
+		return nil, fmt.Errorf("❌ %w", err)
+	}
+
+	var values []generated.ConfigResponse
+	for _, target := range targets {
+		for _, key := range adder.ConfigKeys {
+			value, err := target.Get(key)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, generated.ConfigResponse{
+				Target: target.Name,
+				Key:    key,
+				Value:  value,
+				Source: target.Source(key),
+			})
+		}
+	}
+
+	return values, nil
+}
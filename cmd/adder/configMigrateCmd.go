@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated/config"
+	"github.com/spf13/cobra"
+)
+
+// configMigrateCmd rewrites the deprecated keys of the config file
+func configMigrateCmd(cmd *cobra.Command, req *config.MigrateRequest) error {
+	path, err := configPath(cmd)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("❌ No config file found, nothing to migrate")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("❌ Reading config file failed: %w", err)
+	}
+	migrated, changes, err := adder.MigrateConfig(data)
+	if err != nil {
+		return fmt.Errorf("❌ Migrating %s failed: %w", path, err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("✅ %s has no deprecated keys\n", path)
+		return nil
+	}
+
+	if req.Flags.DryRun {
+		fmt.Printf("🔍 Would rewrite %d keys in %s:\n", len(changes), path)
+	} else {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("❌ Reading config file failed: %w", err)
+		}
+		if err := os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("❌ Writing config file failed: %w", err)
+		}
+		fmt.Printf("✅ Rewrote %d keys in %s:\n", len(changes), path)
+	}
+	for _, change := range changes {
+		fmt.Printf("   %s\n", change)
+	}
+
+	return nil
+}
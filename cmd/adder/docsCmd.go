@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+// docsCmd writes a reference documentation site for the parsed command tree
+func docsCmd(cmd *cobra.Command, req *generated.DocsRequest) error {
+	// Load config from --config or the nearest config file
+	fileConfig, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Merge command line flags with config file (flags take precedence)
+	config, err := applyFlags(cmd, fileConfig,
+		flagOverride{"binary-name", "binary_name", req.Flags.BinaryName},
+		flagOverride{"input", "input", req.Flags.Input},
+	)
+	if err != nil {
+		return err
+	}
+	if config.BinaryName == "" {
+		return fmt.Errorf("binary_name is required. Set it in .adder.yaml or use --binary-name flag")
+	}
+
+	fmt.Printf("📚 Generating %s reference docs from %s to %s...\n", req.Flags.Format, config.InputDir, req.Flags.Output)
+
+	tree, err := adder.New(config).CommandTree()
+	if err != nil {
+		return fmt.Errorf("❌ Parsing failed: %w", err)
+	}
+
+	paths, err := adder.WriteReferenceDocs(tree, req.Flags.Output, req.Flags.Format)
+	if err != nil {
+		return fmt.Errorf("❌ Docs generation failed: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %d reference pages:\n", len(paths))
+	for _, path := range paths {
+		fmt.Printf("  - %s\n", path)
+	}
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/jrschumacher/adder"
@@ -10,27 +9,70 @@ import (
 )
 
 // generateCmd processes the generate command request to create CLI command stubs.
-func generateCmd(_ *cobra.Command, req *generated.GenerateRequest) error {
-	// Load config from file if it exists
-	fileConfig, err := adder.LoadConfig(".")
+func generateCmd(cmd *cobra.Command, req *generated.GenerateRequest) error {
+	// Load config from --config or the nearest config file
+	fileConfig, err := loadConfig(cmd)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not load config file: %v\n", err)
-		fileConfig = adder.DefaultConfig()
+		return err
 	}
 
-	// Merge command line flags with config file (flags take precedence)
-	config := adder.MergeWithFlags(fileConfig, req.Flags.BinaryName, req.Flags.Input, req.Flags.Output, req.Flags.Package, req.Flags.Suffix)
+	targets, err := fileConfig.SelectTargets(req.Flags.Target)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	configs := make([]*adder.Config, len(targets))
+	for i, target := range targets {
+		// Merge command line flags with config file (flags take precedence)
+		config, err := applyFlags(cmd, target,
+			flagOverride{"binary-name", "binary_name", req.Flags.BinaryName},
+			flagOverride{"input", "input", req.Flags.Input},
+			flagOverride{"output", "output", req.Flags.Output},
+			flagOverride{"package", "package", req.Flags.Package},
+			flagOverride{"suffix", "generated_file_suffix", req.Flags.Suffix},
+			flagOverride{"package-strategy", "package_strategy", req.Flags.PackageStrategy},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Validate that binary_name is set (either from config or flag)
+		if config.BinaryName == "" {
+			return fmt.Errorf("binary_name is required. Set it in .adder.yaml or use --binary-name flag")
+		}
+		configs[i] = config
+	}
+
+	// Flags apply to every selected target, so they can make outputs collide
+	if err := adder.CheckTargetOutputs(configs); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if req.Flags.Check || req.Flags.Diff {
+		return checkGenerated(cmd, configs, req)
+	}
 
-	// Override package strategy if provided
-	if req.Flags.PackageStrategy != "" && req.Flags.PackageStrategy != "directory" {
-		config.PackageStrategy = req.Flags.PackageStrategy
+	for _, config := range configs {
+		if config.Name != "" {
+			fmt.Printf("🎯 Target %s\n", config.Name)
+		}
+		if err := generateTarget(cmd, config, req); err != nil {
+			return err
+		}
 	}
 
-	// Validate that binary_name is set (either from config or flag)
-	if config.BinaryName == "" {
-		return fmt.Errorf("binary_name is required. Set it in .adder.yaml or use --binary-name flag")
+	if !req.Flags.Validate {
+		fmt.Println("\n💡 Next steps:")
+		fmt.Println("  1. Implement handler interfaces in your handlers package")
+		fmt.Println("  2. Wire commands using the generated constructors")
+		fmt.Println("  3. Add commands to your root command")
 	}
 
+	return nil
+}
+
+// generateTarget validates and generates the commands of a single target
+func generateTarget(cmd *cobra.Command, config *adder.Config, req *generated.GenerateRequest) error {
 	if req.Flags.Validate {
 		fmt.Printf("🔍 Validating documentation in %s...\n", config.InputDir)
 	} else {
@@ -85,9 +127,10 @@ func generateCmd(_ *cobra.Command, req *generated.GenerateRequest) error {
 	}
 
 	// Generate command stubs
-	ctx := context.Background()
+	ctx := commandContext(cmd)
 	opts := adder.GenerateOptions{
-		Force: req.Flags.Force,
+		Force:     req.Flags.Force,
+		KeepStale: req.Flags.KeepStale,
 	}
 	if err := generator.GenerateWithOptions(ctx, opts); err != nil {
 		return fmt.Errorf("❌ Generation failed: %w", err)
@@ -106,6 +149,14 @@ func generateCmd(_ *cobra.Command, req *generated.GenerateRequest) error {
 			stats["total_commands"], stats["total_flags"], stats["total_arguments"])
 	}
 
+	if removed := generator.GetRemovedFiles(); len(removed) > 0 {
+		fmt.Printf("🧹 Removed %d stale files:\n", len(removed))
+		for _, file := range removed {
+			fmt.Printf("  - %s\n", file)
+		}
+	}
+	printKeptFiles(generator.GetKeptFiles())
+
 	fmt.Println("\n📋 Generated commands:")
 	for _, command := range commands {
 		if command.Name != "" {
@@ -113,10 +164,55 @@ func generateCmd(_ *cobra.Command, req *generated.GenerateRequest) error {
 		}
 	}
 
-	fmt.Println("\n💡 Next steps:")
-	fmt.Println("  1. Implement handler interfaces in your handlers package")
-	fmt.Println("  2. Wire commands using the generated constructors")
-	fmt.Println("  3. Add commands to your root command")
-
 	return nil
 }
+
+// checkGenerated reports pending changes to generated files of every target without writing anything
+// The diff goes to stdout so it can be piped; status messages go to stderr
+func checkGenerated(cmd *cobra.Command, configs []*adder.Config, req *generated.GenerateRequest) error {
+	stderr := cmd.ErrOrStderr()
+
+	var changes []adder.FileChange
+	for _, config := range configs {
+		generator := adder.New(config)
+
+		if err := generator.Validate(); err != nil {
+			fmt.Fprintf(stderr, "⚠️  Validation warnings: %v\n", err)
+		}
+
+		all, err := generator.Changes(commandContext(cmd))
+		if err != nil {
+			return fmt.Errorf("❌ Checking generated files failed: %w", err)
+		}
+
+		// Stale files are only pending deletions when generate would remove them
+		for _, change := range all {
+			if change.Action == adder.ChangeDelete && req.Flags.KeepStale {
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	if req.Flags.Diff {
+		for _, change := range changes {
+			fmt.Fprint(cmd.OutOrStdout(), change.Diff())
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(stderr, "✅ Generated files are up to date")
+		return nil
+	}
+	if !req.Flags.Check {
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "❌ %d generated files are out of date:\n", len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(stderr, "  - %s (%s)\n", change.Path, change.Action)
+	}
+	fmt.Fprintln(stderr, "\n💡 Run adder generate to update them")
+
+	return generated.NewGenerateOutOfDateError("%d generated files are out of date", len(changes))
+}
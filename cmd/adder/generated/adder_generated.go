@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: adder.md
 
 package generated
 
@@ -9,8 +10,9 @@ import (
 
 // AdderRequestPersistentFlags represents the persistent flags for the adder command
 type AdderRequestPersistentFlags struct {
-	Verbose bool `json:"verbose"` // Enable verbose output for debugging and CI
-	Quiet   bool `json:"quiet"`   // Suppress all output except errors
+	Verbose bool   `json:"verbose"` // Enable verbose output for debugging and CI
+	Quiet   bool   `json:"quiet"`   // Suppress all output except errors
+	Config  string `json:"config"`  // Path to the config file, instead of the nearest .adder.yaml up to the module root
 }
 
 // AdderRequest represents the parameters for the adder command
@@ -27,42 +29,134 @@ func (r *AdderRequest) GetRawArguments() []string {
 // Ensure AdderRequest implements adder.Request interface at compile time
 var _ adder.Request = (*AdderRequest)(nil)
 
+// Ensure AdderRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*AdderRequest)(nil)
+
+// AdderRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var AdderRequestValidateFunc func(req *AdderRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *AdderRequest) Validate() error {
+
+	if AdderRequestValidateFunc != nil {
+		return AdderRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultAdderRequest returns a AdderRequest populated with the declared flag defaults
+func DefaultAdderRequest() *AdderRequest {
+	return &AdderRequest{
+		PersistentFlags: AdderRequestPersistentFlags{
+			Verbose: false,
+			Quiet:   false,
+		},
+	}
+}
+
+// AdderRequestBuilder builds AdderRequest values, e.g. for testing handlers
+type AdderRequestBuilder struct {
+	req AdderRequest
+}
+
+// NewAdderRequestBuilder creates a builder starting from DefaultAdderRequest
+func NewAdderRequestBuilder() *AdderRequestBuilder {
+	return &AdderRequestBuilder{req: *DefaultAdderRequest()}
+}
+
+// WithVerbose sets the verbose persistent flag
+func (b *AdderRequestBuilder) WithVerbose(value bool) *AdderRequestBuilder {
+	b.req.PersistentFlags.Verbose = value
+	return b
+}
+
+// WithQuiet sets the quiet persistent flag
+func (b *AdderRequestBuilder) WithQuiet(value bool) *AdderRequestBuilder {
+	b.req.PersistentFlags.Quiet = value
+	return b
+}
+
+// WithConfig sets the config persistent flag
+func (b *AdderRequestBuilder) WithConfig(value string) *AdderRequestBuilder {
+	b.req.PersistentFlags.Config = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *AdderRequestBuilder) Build() *AdderRequest {
+	req := b.req
+	return &req
+}
+
 // AdderHandler defines the function type for handling adder commands
 type AdderHandler func(cmd *cobra.Command, req *AdderRequest) error
 
 // NewAdderCommand creates a new adder command with the provided handler function
-func NewAdderCommand(handler AdderHandler) *cobra.Command {
+func NewAdderCommand(handler AdderHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:   "adder",
 		Short: "A documentation-driven CLI generator",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runAdder(cmd, args, handler)
+			return runAdder(cmd, args, handler, options)
 		},
 	}
 
 	// Register persistent flags
 	cmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output for debugging and CI")
 	cmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress all output except errors")
+	cmd.PersistentFlags().String("config", "", "Path to the config file, instead of the nearest .adder.yaml up to the module root")
 
 	// Register flags
 
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
 	return cmd
 }
 
 // runAdder handles argument and flag extraction
-func runAdder(cmd *cobra.Command, args []string, handler AdderHandler) error {
+func runAdder(cmd *cobra.Command, args []string, handler AdderHandler, options *adder.CommandOptions) error {
 	verbose, _ := cmd.PersistentFlags().GetBool("verbose")
 	quiet, _ := cmd.PersistentFlags().GetBool("quiet")
+	config, _ := cmd.PersistentFlags().GetString("config")
 
 	// Create request
 	req := &AdderRequest{
 		PersistentFlags: AdderRequestPersistentFlags{
 			Verbose: verbose,
 			Quiet:   quiet,
+			Config:  config,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.PersistentFlags().Changed("verbose") {
+			req.PersistentFlags.Verbose = verbose
+		}
+		if cmd.PersistentFlags().Changed("quiet") {
+			req.PersistentFlags.Quiet = quiet
+		}
+		if cmd.PersistentFlags().Changed("config") {
+			req.PersistentFlags.Config = config
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*AdderRequest))
+	})(cmd, req)
 }
@@ -0,0 +1,190 @@
+// Code generated by adder. DO NOT EDIT.
+// Source: clean.md
+
+package generated
+
+import (
+	"github.com/jrschumacher/adder"
+	"github.com/spf13/cobra"
+)
+
+// CleanRequestFlags represents the flags for the clean command
+type CleanRequestFlags struct {
+	Input  string `json:"input"`  // Input directory containing markdown files
+	Output string `json:"output"` // Output directory for generated files
+	All    bool   `json:"all"`    // Remove every generated file, not only stale ones
+	DryRun bool   `json:"dryRun"` // List the files that would be removed without removing them
+	Target string `json:"target"` // Clean only the named target of the config file (default all targets)
+}
+
+// CleanRequest represents the parameters for the clean command
+type CleanRequest struct {
+	Flags        CleanRequestFlags `json:"flags"`
+	RawArguments []string          `json:"raw_arguments"` // Raw command line arguments passed to the command
+}
+
+// GetRawArguments implements the adder.Request interface
+func (r *CleanRequest) GetRawArguments() []string {
+	return r.RawArguments
+}
+
+// Ensure CleanRequest implements adder.Request interface at compile time
+var _ adder.Request = (*CleanRequest)(nil)
+
+// Ensure CleanRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*CleanRequest)(nil)
+
+// CleanRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var CleanRequestValidateFunc func(req *CleanRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *CleanRequest) Validate() error {
+
+	if CleanRequestValidateFunc != nil {
+		return CleanRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultCleanRequest returns a CleanRequest populated with the declared flag defaults
+func DefaultCleanRequest() *CleanRequest {
+	return &CleanRequest{
+		Flags: CleanRequestFlags{
+			Input:  "docs/commands",
+			Output: "generated",
+			All:    false,
+			DryRun: false,
+		},
+	}
+}
+
+// CleanRequestBuilder builds CleanRequest values, e.g. for testing handlers
+type CleanRequestBuilder struct {
+	req CleanRequest
+}
+
+// NewCleanRequestBuilder creates a builder starting from DefaultCleanRequest
+func NewCleanRequestBuilder() *CleanRequestBuilder {
+	return &CleanRequestBuilder{req: *DefaultCleanRequest()}
+}
+
+// WithInput sets the input flag
+func (b *CleanRequestBuilder) WithInput(value string) *CleanRequestBuilder {
+	b.req.Flags.Input = value
+	return b
+}
+
+// WithOutput sets the output flag
+func (b *CleanRequestBuilder) WithOutput(value string) *CleanRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithAll sets the all flag
+func (b *CleanRequestBuilder) WithAll(value bool) *CleanRequestBuilder {
+	b.req.Flags.All = value
+	return b
+}
+
+// WithDryRun sets the dry-run flag
+func (b *CleanRequestBuilder) WithDryRun(value bool) *CleanRequestBuilder {
+	b.req.Flags.DryRun = value
+	return b
+}
+
+// WithTarget sets the target flag
+func (b *CleanRequestBuilder) WithTarget(value string) *CleanRequestBuilder {
+	b.req.Flags.Target = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *CleanRequestBuilder) Build() *CleanRequest {
+	req := b.req
+	return &req
+}
+
+// CleanHandler defines the function type for handling clean commands
+type CleanHandler func(cmd *cobra.Command, req *CleanRequest) error
+
+// NewCleanCommand creates a new clean command with the provided handler function
+func NewCleanCommand(handler CleanHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove generated files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runClean(cmd, args, handler, options)
+		},
+	}
+
+	// Register persistent flags
+
+	// Register flags
+	cmd.Flags().StringP("input", "i", "docs/commands", "Input directory containing markdown files")
+	cmd.Flags().StringP("output", "o", "generated", "Output directory for generated files")
+	cmd.Flags().Bool("all", false, "Remove every generated file, not only stale ones")
+	cmd.Flags().Bool("dry-run", false, "List the files that would be removed without removing them")
+	cmd.Flags().StringP("target", "t", "", "Clean only the named target of the config file (default all targets)")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
+	return cmd
+}
+
+// runClean handles argument and flag extraction
+func runClean(cmd *cobra.Command, args []string, handler CleanHandler, options *adder.CommandOptions) error {
+	input, _ := cmd.Flags().GetString("input")
+	output, _ := cmd.Flags().GetString("output")
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	target, _ := cmd.Flags().GetString("target")
+
+	// Create request
+	req := &CleanRequest{
+		Flags: CleanRequestFlags{
+			Input:  input,
+			Output: output,
+			All:    all,
+			DryRun: dryRun,
+			Target: target,
+		},
+	}
+
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("input") {
+			req.Flags.Input = input
+		}
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("all") {
+			req.Flags.All = all
+		}
+		if cmd.Flags().Changed("dry-run") {
+			req.Flags.DryRun = dryRun
+		}
+		if cmd.Flags().Changed("target") {
+			req.Flags.Target = target
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*CleanRequest))
+	})(cmd, req)
+}
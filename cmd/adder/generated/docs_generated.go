@@ -0,0 +1,179 @@
+// Code generated by adder. DO NOT EDIT.
+// Source: docs.md
+
+package generated
+
+import (
+	"github.com/jrschumacher/adder"
+	"github.com/spf13/cobra"
+)
+
+// DocsRequestFlags represents the flags for the docs command
+type DocsRequestFlags struct {
+	BinaryName string `json:"binaryName"`                                       // Name of the binary/CLI (required unless set in config)
+	Input      string `json:"input"`                                            // Input directory containing markdown files
+	Output     string `json:"output"`                                           // Output directory for the reference pages
+	Format     string `json:"format" validate:"oneof=markdown hugo docusaurus"` // Frontmatter and layout of the pages
+}
+
+// DocsRequest represents the parameters for the docs command
+type DocsRequest struct {
+	Flags        DocsRequestFlags `json:"flags"`
+	RawArguments []string         `json:"raw_arguments"` // Raw command line arguments passed to the command
+}
+
+// GetRawArguments implements the adder.Request interface
+func (r *DocsRequest) GetRawArguments() []string {
+	return r.RawArguments
+}
+
+// Ensure DocsRequest implements adder.Request interface at compile time
+var _ adder.Request = (*DocsRequest)(nil)
+
+// Ensure DocsRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*DocsRequest)(nil)
+
+// DocsRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var DocsRequestValidateFunc func(req *DocsRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *DocsRequest) Validate() error {
+	if err := adder.ValidateEnum("format", r.Flags.Format, []string{"markdown", "hugo", "docusaurus"}); err != nil {
+		return err
+	}
+
+	if DocsRequestValidateFunc != nil {
+		return DocsRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultDocsRequest returns a DocsRequest populated with the declared flag defaults
+func DefaultDocsRequest() *DocsRequest {
+	return &DocsRequest{
+		Flags: DocsRequestFlags{
+			Input:  "docs/commands",
+			Output: "docs/reference",
+			Format: "markdown",
+		},
+	}
+}
+
+// DocsRequestBuilder builds DocsRequest values, e.g. for testing handlers
+type DocsRequestBuilder struct {
+	req DocsRequest
+}
+
+// NewDocsRequestBuilder creates a builder starting from DefaultDocsRequest
+func NewDocsRequestBuilder() *DocsRequestBuilder {
+	return &DocsRequestBuilder{req: *DefaultDocsRequest()}
+}
+
+// WithBinaryName sets the binary-name flag
+func (b *DocsRequestBuilder) WithBinaryName(value string) *DocsRequestBuilder {
+	b.req.Flags.BinaryName = value
+	return b
+}
+
+// WithInput sets the input flag
+func (b *DocsRequestBuilder) WithInput(value string) *DocsRequestBuilder {
+	b.req.Flags.Input = value
+	return b
+}
+
+// WithOutput sets the output flag
+func (b *DocsRequestBuilder) WithOutput(value string) *DocsRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithFormat sets the format flag
+func (b *DocsRequestBuilder) WithFormat(value string) *DocsRequestBuilder {
+	b.req.Flags.Format = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *DocsRequestBuilder) Build() *DocsRequest {
+	req := b.req
+	return &req
+}
+
+// DocsHandler defines the function type for handling docs commands
+type DocsHandler func(cmd *cobra.Command, req *DocsRequest) error
+
+// NewDocsCommand creates a new docs command with the provided handler function
+func NewDocsCommand(handler DocsHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate a reference documentation site from command markdown",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDocs(cmd, args, handler, options)
+		},
+	}
+
+	// Register persistent flags
+
+	// Register flags
+	cmd.Flags().StringP("binary-name", "b", "", "Name of the binary/CLI (required unless set in config)")
+	cmd.Flags().StringP("input", "i", "docs/commands", "Input directory containing markdown files")
+	cmd.Flags().StringP("output", "o", "docs/reference", "Output directory for the reference pages")
+	cmd.Flags().StringP("format", "f", "markdown", "Frontmatter and layout of the pages")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
+	return cmd
+}
+
+// runDocs handles argument and flag extraction
+func runDocs(cmd *cobra.Command, args []string, handler DocsHandler, options *adder.CommandOptions) error {
+	binaryName, _ := cmd.Flags().GetString("binary-name")
+	input, _ := cmd.Flags().GetString("input")
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+
+	// Create request
+	req := &DocsRequest{
+		Flags: DocsRequestFlags{
+			BinaryName: binaryName,
+			Input:      input,
+			Output:     output,
+			Format:     format,
+		},
+	}
+
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("binary-name") {
+			req.Flags.BinaryName = binaryName
+		}
+		if cmd.Flags().Changed("input") {
+			req.Flags.Input = input
+		}
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("format") {
+			req.Flags.Format = format
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*DocsRequest))
+	})(cmd, req)
+}
@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: generate.md
 
 package generated
 
@@ -15,8 +16,12 @@ type GenerateRequestFlags struct {
 	Package         string `json:"pkg"`             // Go package name for generated files
 	Suffix          string `json:"suffix"`          // File suffix for generated files
 	Validate        bool   `json:"validate"`        // Validate documentation without generating files
-	Force           bool   `json:"force"`           // Force regeneration of all files regardless of modification time
+	Force           bool   `json:"force"`           // Force regeneration of all files, ignoring the .adder.lock manifest
 	PackageStrategy string `json:"packageStrategy"` // Package naming strategy (single, directory, path)
+	Check           bool   `json:"check"`           // Fail if generated files are out of date, without writing anything
+	Diff            bool   `json:"diff"`            // Print a unified diff of pending changes, without writing anything
+	KeepStale       bool   `json:"keepStale"`       // Keep generated files whose markdown source was deleted or renamed
+	Target          string `json:"target"`          // Generate only the named target of the config file (default all targets)
 }
 
 // GenerateRequest represents the parameters for the generate command
@@ -33,16 +38,156 @@ func (r *GenerateRequest) GetRawArguments() []string {
 // Ensure GenerateRequest implements adder.Request interface at compile time
 var _ adder.Request = (*GenerateRequest)(nil)
 
+// Ensure GenerateRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*GenerateRequest)(nil)
+
+// GenerateRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var GenerateRequestValidateFunc func(req *GenerateRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *GenerateRequest) Validate() error {
+	if err := adder.ValidateMutuallyExclusive(adder.FieldSet{Name: "check", Set: r.Flags.Check != false}, adder.FieldSet{Name: "validate", Set: r.Flags.Validate != false}); err != nil {
+		return err
+	}
+	if err := adder.ValidateMutuallyExclusive(adder.FieldSet{Name: "diff", Set: r.Flags.Diff != false}, adder.FieldSet{Name: "validate", Set: r.Flags.Validate != false}); err != nil {
+		return err
+	}
+
+	if GenerateRequestValidateFunc != nil {
+		return GenerateRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultGenerateRequest returns a GenerateRequest populated with the declared flag defaults
+func DefaultGenerateRequest() *GenerateRequest {
+	return &GenerateRequest{
+		Flags: GenerateRequestFlags{
+			Input:           "docs/commands",
+			Output:          "generated",
+			Package:         "generated",
+			Suffix:          "_generated.go",
+			Validate:        false,
+			Force:           false,
+			PackageStrategy: "directory",
+			Check:           false,
+			Diff:            false,
+			KeepStale:       false,
+		},
+	}
+}
+
+// GenerateRequestBuilder builds GenerateRequest values, e.g. for testing handlers
+type GenerateRequestBuilder struct {
+	req GenerateRequest
+}
+
+// NewGenerateRequestBuilder creates a builder starting from DefaultGenerateRequest
+func NewGenerateRequestBuilder() *GenerateRequestBuilder {
+	return &GenerateRequestBuilder{req: *DefaultGenerateRequest()}
+}
+
+// WithBinaryName sets the binary-name flag
+func (b *GenerateRequestBuilder) WithBinaryName(value string) *GenerateRequestBuilder {
+	b.req.Flags.BinaryName = value
+	return b
+}
+
+// WithInput sets the input flag
+func (b *GenerateRequestBuilder) WithInput(value string) *GenerateRequestBuilder {
+	b.req.Flags.Input = value
+	return b
+}
+
+// WithOutput sets the output flag
+func (b *GenerateRequestBuilder) WithOutput(value string) *GenerateRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithPackage sets the package flag
+func (b *GenerateRequestBuilder) WithPackage(value string) *GenerateRequestBuilder {
+	b.req.Flags.Package = value
+	return b
+}
+
+// WithSuffix sets the suffix flag
+func (b *GenerateRequestBuilder) WithSuffix(value string) *GenerateRequestBuilder {
+	b.req.Flags.Suffix = value
+	return b
+}
+
+// WithValidate sets the validate flag
+func (b *GenerateRequestBuilder) WithValidate(value bool) *GenerateRequestBuilder {
+	b.req.Flags.Validate = value
+	return b
+}
+
+// WithForce sets the force flag
+func (b *GenerateRequestBuilder) WithForce(value bool) *GenerateRequestBuilder {
+	b.req.Flags.Force = value
+	return b
+}
+
+// WithPackageStrategy sets the package-strategy flag
+func (b *GenerateRequestBuilder) WithPackageStrategy(value string) *GenerateRequestBuilder {
+	b.req.Flags.PackageStrategy = value
+	return b
+}
+
+// WithCheck sets the check flag
+func (b *GenerateRequestBuilder) WithCheck(value bool) *GenerateRequestBuilder {
+	b.req.Flags.Check = value
+	return b
+}
+
+// WithDiff sets the diff flag
+func (b *GenerateRequestBuilder) WithDiff(value bool) *GenerateRequestBuilder {
+	b.req.Flags.Diff = value
+	return b
+}
+
+// WithKeepStale sets the keep-stale flag
+func (b *GenerateRequestBuilder) WithKeepStale(value bool) *GenerateRequestBuilder {
+	b.req.Flags.KeepStale = value
+	return b
+}
+
+// WithTarget sets the target flag
+func (b *GenerateRequestBuilder) WithTarget(value string) *GenerateRequestBuilder {
+	b.req.Flags.Target = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *GenerateRequestBuilder) Build() *GenerateRequest {
+	req := b.req
+	return &req
+}
+
 // GenerateHandler defines the function type for handling generate commands
 type GenerateHandler func(cmd *cobra.Command, req *GenerateRequest) error
 
+// GenerateOutOfDateExitCode is the exit code of out-of-date errors of the generate command
+const GenerateOutOfDateExitCode = 4
+
+// NewGenerateOutOfDateError creates a out-of-date error that exits with code 4
+// Generated files differ from the markdown (--check)
+func NewGenerateOutOfDateError(format string, args ...interface{}) error {
+	return adder.Errorf(GenerateOutOfDateExitCode, format, args...)
+}
+
 // NewGenerateCommand creates a new generate command with the provided handler function
-func NewGenerateCommand(handler GenerateHandler) *cobra.Command {
+func NewGenerateCommand(handler GenerateHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate CLI commands from markdown documentation",
+		Long:  "Generate CLI commands from markdown documentation\n\nExit Codes:\n  0   success\n  1   unexpected error\n  2   invalid usage\n  3   invalid request\n  4   out-of-date: Generated files differ from the markdown (--check)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runGenerate(cmd, args, handler)
+			return runGenerate(cmd, args, handler, options)
 		},
 	}
 
@@ -55,14 +200,25 @@ func NewGenerateCommand(handler GenerateHandler) *cobra.Command {
 	cmd.Flags().StringP("package", "p", "generated", "Go package name for generated files")
 	cmd.Flags().String("suffix", "_generated.go", "File suffix for generated files")
 	cmd.Flags().Bool("validate", false, "Validate documentation without generating files")
-	cmd.Flags().BoolP("force", "f", false, "Force regeneration of all files regardless of modification time")
+	cmd.Flags().BoolP("force", "f", false, "Force regeneration of all files, ignoring the .adder.lock manifest")
 	cmd.Flags().String("package-strategy", "directory", "Package naming strategy (single, directory, path)")
+	cmd.Flags().Bool("check", false, "Fail if generated files are out of date, without writing anything")
+	cmd.Flags().Bool("diff", false, "Print a unified diff of pending changes, without writing anything")
+	cmd.Flags().Bool("keep-stale", false, "Keep generated files whose markdown source was deleted or renamed")
+	cmd.Flags().StringP("target", "t", "", "Generate only the named target of the config file (default all targets)")
+
+	// Register flag rules
+	cmd.MarkFlagsMutuallyExclusive("check", "validate")
+	cmd.MarkFlagsMutuallyExclusive("diff", "validate")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
 
 	return cmd
 }
 
 // runGenerate handles argument and flag extraction
-func runGenerate(cmd *cobra.Command, args []string, handler GenerateHandler) error {
+func runGenerate(cmd *cobra.Command, args []string, handler GenerateHandler, options *adder.CommandOptions) error {
 	binaryName, _ := cmd.Flags().GetString("binary-name")
 	input, _ := cmd.Flags().GetString("input")
 	output, _ := cmd.Flags().GetString("output")
@@ -71,6 +227,10 @@ func runGenerate(cmd *cobra.Command, args []string, handler GenerateHandler) err
 	validate, _ := cmd.Flags().GetBool("validate")
 	force, _ := cmd.Flags().GetBool("force")
 	packageStrategy, _ := cmd.Flags().GetString("package-strategy")
+	check, _ := cmd.Flags().GetBool("check")
+	diff, _ := cmd.Flags().GetBool("diff")
+	keepStale, _ := cmd.Flags().GetBool("keep-stale")
+	target, _ := cmd.Flags().GetString("target")
 
 	// Create request
 	req := &GenerateRequest{
@@ -83,10 +243,65 @@ func runGenerate(cmd *cobra.Command, args []string, handler GenerateHandler) err
 			Validate:        validate,
 			Force:           force,
 			PackageStrategy: packageStrategy,
+			Check:           check,
+			Diff:            diff,
+			KeepStale:       keepStale,
+			Target:          target,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("binary-name") {
+			req.Flags.BinaryName = binaryName
+		}
+		if cmd.Flags().Changed("input") {
+			req.Flags.Input = input
+		}
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("package") {
+			req.Flags.Package = pkg
+		}
+		if cmd.Flags().Changed("suffix") {
+			req.Flags.Suffix = suffix
+		}
+		if cmd.Flags().Changed("validate") {
+			req.Flags.Validate = validate
+		}
+		if cmd.Flags().Changed("force") {
+			req.Flags.Force = force
+		}
+		if cmd.Flags().Changed("package-strategy") {
+			req.Flags.PackageStrategy = packageStrategy
+		}
+		if cmd.Flags().Changed("check") {
+			req.Flags.Check = check
+		}
+		if cmd.Flags().Changed("diff") {
+			req.Flags.Diff = diff
+		}
+		if cmd.Flags().Changed("keep-stale") {
+			req.Flags.KeepStale = keepStale
+		}
+		if cmd.Flags().Changed("target") {
+			req.Flags.Target = target
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*GenerateRequest))
+	})(cmd, req)
 }
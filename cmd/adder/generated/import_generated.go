@@ -0,0 +1,194 @@
+// Code generated by adder. DO NOT EDIT.
+// Source: import.md
+
+package generated
+
+import (
+	"github.com/jrschumacher/adder"
+	"github.com/spf13/cobra"
+)
+
+// ImportRequestArguments represents the arguments for the import [path] command
+type ImportRequestArguments struct {
+	Path string `json:"path" validate:"required"` // Directory containing the Go source of the cobra commands
+}
+
+// ImportRequestFlags represents the flags for the import [path] command
+type ImportRequestFlags struct {
+	BinaryName string `json:"binaryName"` // Name of the binary/CLI (defaults to the root command's name)
+	Output     string `json:"output"`     // Directory to write markdown files to (defaults to the configured input directory)
+	Force      bool   `json:"force"`      // Overwrite existing markdown files
+	DryRun     bool   `json:"dryRun"`     // List the files that would be written without writing them
+}
+
+// ImportRequest represents the parameters for the import [path] command
+type ImportRequest struct {
+	Arguments    ImportRequestArguments `json:"arguments"`
+	Flags        ImportRequestFlags     `json:"flags"`
+	RawArguments []string               `json:"raw_arguments"` // Raw command line arguments passed to the command
+}
+
+// GetRawArguments implements the adder.Request interface
+func (r *ImportRequest) GetRawArguments() []string {
+	return r.RawArguments
+}
+
+// Ensure ImportRequest implements adder.Request interface at compile time
+var _ adder.Request = (*ImportRequest)(nil)
+
+// Ensure ImportRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*ImportRequest)(nil)
+
+// ImportRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var ImportRequestValidateFunc func(req *ImportRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *ImportRequest) Validate() error {
+	if err := adder.ValidateRequired("path", r.Arguments.Path); err != nil {
+		return err
+	}
+
+	if ImportRequestValidateFunc != nil {
+		return ImportRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultImportRequest returns a ImportRequest populated with the declared flag defaults
+func DefaultImportRequest() *ImportRequest {
+	return &ImportRequest{
+		Flags: ImportRequestFlags{
+			Force:  false,
+			DryRun: false,
+		},
+	}
+}
+
+// ImportRequestBuilder builds ImportRequest values, e.g. for testing handlers
+type ImportRequestBuilder struct {
+	req ImportRequest
+}
+
+// NewImportRequestBuilder creates a builder starting from DefaultImportRequest
+func NewImportRequestBuilder() *ImportRequestBuilder {
+	return &ImportRequestBuilder{req: *DefaultImportRequest()}
+}
+
+// WithPath sets the path argument
+func (b *ImportRequestBuilder) WithPath(value string) *ImportRequestBuilder {
+	b.req.Arguments.Path = value
+	return b
+}
+
+// WithBinaryName sets the binary-name flag
+func (b *ImportRequestBuilder) WithBinaryName(value string) *ImportRequestBuilder {
+	b.req.Flags.BinaryName = value
+	return b
+}
+
+// WithOutput sets the output flag
+func (b *ImportRequestBuilder) WithOutput(value string) *ImportRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithForce sets the force flag
+func (b *ImportRequestBuilder) WithForce(value bool) *ImportRequestBuilder {
+	b.req.Flags.Force = value
+	return b
+}
+
+// WithDryRun sets the dry-run flag
+func (b *ImportRequestBuilder) WithDryRun(value bool) *ImportRequestBuilder {
+	b.req.Flags.DryRun = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *ImportRequestBuilder) Build() *ImportRequest {
+	req := b.req
+	return &req
+}
+
+// ImportHandler defines the function type for handling import [path] commands
+type ImportHandler func(cmd *cobra.Command, req *ImportRequest) error
+
+// NewImportCommand creates a new import [path] command with the provided handler function
+func NewImportCommand(handler ImportHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
+	cmd := &cobra.Command{
+		Use:   "import [path]",
+		Short: "Import an existing cobra command tree into markdown",
+		Args:  adder.ExactArgsOrFromFile(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd, args, handler, options)
+		},
+	}
+
+	// Register persistent flags
+
+	// Register flags
+	cmd.Flags().StringP("binary-name", "b", "", "Name of the binary/CLI (defaults to the root command's name)")
+	cmd.Flags().StringP("output", "o", "", "Directory to write markdown files to (defaults to the configured input directory)")
+	cmd.Flags().BoolP("force", "f", false, "Overwrite existing markdown files")
+	cmd.Flags().Bool("dry-run", false, "List the files that would be written without writing them")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
+	return cmd
+}
+
+// runImport handles argument and flag extraction
+func runImport(cmd *cobra.Command, args []string, handler ImportHandler, options *adder.CommandOptions) error {
+	binaryName, _ := cmd.Flags().GetString("binary-name")
+	output, _ := cmd.Flags().GetString("output")
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// Create request
+	req := &ImportRequest{
+		Flags: ImportRequestFlags{
+			BinaryName: binaryName,
+			Output:     output,
+			Force:      force,
+			DryRun:     dryRun,
+		},
+	}
+
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("binary-name") {
+			req.Flags.BinaryName = binaryName
+		}
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("force") {
+			req.Flags.Force = force
+		}
+		if cmd.Flags().Changed("dry-run") {
+			req.Flags.DryRun = dryRun
+		}
+	}
+	if len(args) > 0 {
+		req.Arguments.Path = args[0]
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*ImportRequest))
+	})(cmd, req)
+}
@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: init.md
 
 package generated
 
@@ -27,16 +28,71 @@ func (r *InitRequest) GetRawArguments() []string {
 // Ensure InitRequest implements adder.Request interface at compile time
 var _ adder.Request = (*InitRequest)(nil)
 
+// Ensure InitRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*InitRequest)(nil)
+
+// InitRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var InitRequestValidateFunc func(req *InitRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *InitRequest) Validate() error {
+
+	if InitRequestValidateFunc != nil {
+		return InitRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultInitRequest returns a InitRequest populated with the declared flag defaults
+func DefaultInitRequest() *InitRequest {
+	return &InitRequest{
+		Flags: InitRequestFlags{
+			Force: false,
+		},
+	}
+}
+
+// InitRequestBuilder builds InitRequest values, e.g. for testing handlers
+type InitRequestBuilder struct {
+	req InitRequest
+}
+
+// NewInitRequestBuilder creates a builder starting from DefaultInitRequest
+func NewInitRequestBuilder() *InitRequestBuilder {
+	return &InitRequestBuilder{req: *DefaultInitRequest()}
+}
+
+// WithBinaryName sets the binary-name flag
+func (b *InitRequestBuilder) WithBinaryName(value string) *InitRequestBuilder {
+	b.req.Flags.BinaryName = value
+	return b
+}
+
+// WithForce sets the force flag
+func (b *InitRequestBuilder) WithForce(value bool) *InitRequestBuilder {
+	b.req.Flags.Force = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *InitRequestBuilder) Build() *InitRequest {
+	req := b.req
+	return &req
+}
+
 // InitHandler defines the function type for handling init commands
 type InitHandler func(cmd *cobra.Command, req *InitRequest) error
 
 // NewInitCommand creates a new init command with the provided handler function
-func NewInitCommand(handler InitHandler) *cobra.Command {
+func NewInitCommand(handler InitHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize adder configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInit(cmd, args, handler)
+			return runInit(cmd, args, handler, options)
 		},
 	}
 
@@ -46,11 +102,14 @@ func NewInitCommand(handler InitHandler) *cobra.Command {
 	cmd.Flags().StringP("binary-name", "b", "", "Name of the binary/CLI (required)")
 	cmd.Flags().BoolP("force", "f", false, "Overwrite existing configuration file")
 
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
 	return cmd
 }
 
 // runInit handles argument and flag extraction
-func runInit(cmd *cobra.Command, args []string, handler InitHandler) error {
+func runInit(cmd *cobra.Command, args []string, handler InitHandler, options *adder.CommandOptions) error {
 	binaryName, _ := cmd.Flags().GetString("binary-name")
 	force, _ := cmd.Flags().GetBool("force")
 
@@ -60,9 +119,30 @@ func runInit(cmd *cobra.Command, args []string, handler InitHandler) error {
 			BinaryName: binaryName,
 			Force:      force,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("binary-name") {
+			req.Flags.BinaryName = binaryName
+		}
+		if cmd.Flags().Changed("force") {
+			req.Flags.Force = force
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*InitRequest))
+	})(cmd, req)
 }
@@ -0,0 +1,188 @@
+// Code generated by adder. DO NOT EDIT.
+// Source: man.md
+
+package generated
+
+import (
+	"github.com/jrschumacher/adder"
+	"github.com/spf13/cobra"
+)
+
+// ManRequestFlags represents the flags for the man command
+type ManRequestFlags struct {
+	BinaryName string `json:"binaryName"` // Name of the binary/CLI (required unless set in config)
+	Input      string `json:"input"`      // Input directory containing markdown files
+	Output     string `json:"output"`     // Output directory for the man pages
+	Source     string `json:"source"`     // Source shown in the page footer, e.g. \"myapp 1.2.0\"
+	Manual     string `json:"manual"`     // Manual title shown in the page header
+}
+
+// ManRequest represents the parameters for the man command
+type ManRequest struct {
+	Flags        ManRequestFlags `json:"flags"`
+	RawArguments []string        `json:"raw_arguments"` // Raw command line arguments passed to the command
+}
+
+// GetRawArguments implements the adder.Request interface
+func (r *ManRequest) GetRawArguments() []string {
+	return r.RawArguments
+}
+
+// Ensure ManRequest implements adder.Request interface at compile time
+var _ adder.Request = (*ManRequest)(nil)
+
+// Ensure ManRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*ManRequest)(nil)
+
+// ManRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var ManRequestValidateFunc func(req *ManRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *ManRequest) Validate() error {
+
+	if ManRequestValidateFunc != nil {
+		return ManRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultManRequest returns a ManRequest populated with the declared flag defaults
+func DefaultManRequest() *ManRequest {
+	return &ManRequest{
+		Flags: ManRequestFlags{
+			Input:  "docs/commands",
+			Output: "man",
+		},
+	}
+}
+
+// ManRequestBuilder builds ManRequest values, e.g. for testing handlers
+type ManRequestBuilder struct {
+	req ManRequest
+}
+
+// NewManRequestBuilder creates a builder starting from DefaultManRequest
+func NewManRequestBuilder() *ManRequestBuilder {
+	return &ManRequestBuilder{req: *DefaultManRequest()}
+}
+
+// WithBinaryName sets the binary-name flag
+func (b *ManRequestBuilder) WithBinaryName(value string) *ManRequestBuilder {
+	b.req.Flags.BinaryName = value
+	return b
+}
+
+// WithInput sets the input flag
+func (b *ManRequestBuilder) WithInput(value string) *ManRequestBuilder {
+	b.req.Flags.Input = value
+	return b
+}
+
+// WithOutput sets the output flag
+func (b *ManRequestBuilder) WithOutput(value string) *ManRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithSource sets the source flag
+func (b *ManRequestBuilder) WithSource(value string) *ManRequestBuilder {
+	b.req.Flags.Source = value
+	return b
+}
+
+// WithManual sets the manual flag
+func (b *ManRequestBuilder) WithManual(value string) *ManRequestBuilder {
+	b.req.Flags.Manual = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *ManRequestBuilder) Build() *ManRequest {
+	req := b.req
+	return &req
+}
+
+// ManHandler defines the function type for handling man commands
+type ManHandler func(cmd *cobra.Command, req *ManRequest) error
+
+// NewManCommand creates a new man command with the provided handler function
+func NewManCommand(handler ManHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
+	cmd := &cobra.Command{
+		Use:   "man",
+		Short: "Generate man pages from command markdown",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMan(cmd, args, handler, options)
+		},
+	}
+
+	// Register persistent flags
+
+	// Register flags
+	cmd.Flags().StringP("binary-name", "b", "", "Name of the binary/CLI (required unless set in config)")
+	cmd.Flags().StringP("input", "i", "docs/commands", "Input directory containing markdown files")
+	cmd.Flags().StringP("output", "o", "man", "Output directory for the man pages")
+	cmd.Flags().String("source", "", "Source shown in the page footer, e.g. \"myapp 1.2.0\"")
+	cmd.Flags().String("manual", "", "Manual title shown in the page header")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
+	return cmd
+}
+
+// runMan handles argument and flag extraction
+func runMan(cmd *cobra.Command, args []string, handler ManHandler, options *adder.CommandOptions) error {
+	binaryName, _ := cmd.Flags().GetString("binary-name")
+	input, _ := cmd.Flags().GetString("input")
+	output, _ := cmd.Flags().GetString("output")
+	source, _ := cmd.Flags().GetString("source")
+	manual, _ := cmd.Flags().GetString("manual")
+
+	// Create request
+	req := &ManRequest{
+		Flags: ManRequestFlags{
+			BinaryName: binaryName,
+			Input:      input,
+			Output:     output,
+			Source:     source,
+			Manual:     manual,
+		},
+	}
+
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("binary-name") {
+			req.Flags.BinaryName = binaryName
+		}
+		if cmd.Flags().Changed("input") {
+			req.Flags.Input = input
+		}
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("source") {
+			req.Flags.Source = source
+		}
+		if cmd.Flags().Changed("manual") {
+			req.Flags.Manual = manual
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*ManRequest))
+	})(cmd, req)
+}
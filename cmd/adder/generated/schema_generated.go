@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: schema.md
 
 package generated
 
@@ -9,8 +10,9 @@ import (
 
 // SchemaRequestFlags represents the flags for the schema command
 type SchemaRequestFlags struct {
-	Output string `json:"output"`                            // Output file path for the schema
-	Format string `json:"format" validate:"oneof=json yaml"` // Output format
+	Output string `json:"output"`                               // Output file path for the schema
+	Format string `json:"format" validate:"oneof=json yaml"`    // Output format
+	Kind   string `json:"kind" validate:"oneof=command config"` // Schema to generate, for command frontmatter or for the config file
 }
 
 // SchemaRequest represents the parameters for the schema command
@@ -27,16 +29,84 @@ func (r *SchemaRequest) GetRawArguments() []string {
 // Ensure SchemaRequest implements adder.Request interface at compile time
 var _ adder.Request = (*SchemaRequest)(nil)
 
+// Ensure SchemaRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*SchemaRequest)(nil)
+
+// SchemaRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var SchemaRequestValidateFunc func(req *SchemaRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *SchemaRequest) Validate() error {
+	if err := adder.ValidateEnum("format", r.Flags.Format, []string{"json", "yaml"}); err != nil {
+		return err
+	}
+	if err := adder.ValidateEnum("kind", r.Flags.Kind, []string{"command", "config"}); err != nil {
+		return err
+	}
+
+	if SchemaRequestValidateFunc != nil {
+		return SchemaRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultSchemaRequest returns a SchemaRequest populated with the declared flag defaults
+func DefaultSchemaRequest() *SchemaRequest {
+	return &SchemaRequest{
+		Flags: SchemaRequestFlags{
+			Format: "json",
+			Kind:   "command",
+		},
+	}
+}
+
+// SchemaRequestBuilder builds SchemaRequest values, e.g. for testing handlers
+type SchemaRequestBuilder struct {
+	req SchemaRequest
+}
+
+// NewSchemaRequestBuilder creates a builder starting from DefaultSchemaRequest
+func NewSchemaRequestBuilder() *SchemaRequestBuilder {
+	return &SchemaRequestBuilder{req: *DefaultSchemaRequest()}
+}
+
+// WithOutput sets the output flag
+func (b *SchemaRequestBuilder) WithOutput(value string) *SchemaRequestBuilder {
+	b.req.Flags.Output = value
+	return b
+}
+
+// WithFormat sets the format flag
+func (b *SchemaRequestBuilder) WithFormat(value string) *SchemaRequestBuilder {
+	b.req.Flags.Format = value
+	return b
+}
+
+// WithKind sets the kind flag
+func (b *SchemaRequestBuilder) WithKind(value string) *SchemaRequestBuilder {
+	b.req.Flags.Kind = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *SchemaRequestBuilder) Build() *SchemaRequest {
+	req := b.req
+	return &req
+}
+
 // SchemaHandler defines the function type for handling schema commands
 type SchemaHandler func(cmd *cobra.Command, req *SchemaRequest) error
 
 // NewSchemaCommand creates a new schema command with the provided handler function
-func NewSchemaCommand(handler SchemaHandler) *cobra.Command {
+func NewSchemaCommand(handler SchemaHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:   "schema",
 		Short: "Generate JSON Schema for command validation",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSchema(cmd, args, handler)
+			return runSchema(cmd, args, handler, options)
 		},
 	}
 
@@ -45,28 +115,54 @@ func NewSchemaCommand(handler SchemaHandler) *cobra.Command {
 	// Register flags
 	cmd.Flags().StringP("output", "o", "", "Output file path for the schema")
 	cmd.Flags().StringP("format", "f", "json", "Output format")
+	cmd.Flags().StringP("kind", "k", "command", "Schema to generate, for command frontmatter or for the config file")
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
 
 	return cmd
 }
 
 // runSchema handles argument and flag extraction
-func runSchema(cmd *cobra.Command, args []string, handler SchemaHandler) error {
+func runSchema(cmd *cobra.Command, args []string, handler SchemaHandler, options *adder.CommandOptions) error {
 	output, _ := cmd.Flags().GetString("output")
 	format, _ := cmd.Flags().GetString("format")
-	// Validate enum for format
-	if err := adder.ValidateEnum("format", format, []string{"json", "yaml"}); err != nil {
-		return err
-	}
+	kind, _ := cmd.Flags().GetString("kind")
 
 	// Create request
 	req := &SchemaRequest{
 		Flags: SchemaRequestFlags{
 			Output: output,
 			Format: format,
+			Kind:   kind,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("output") {
+			req.Flags.Output = output
+		}
+		if cmd.Flags().Changed("format") {
+			req.Flags.Format = format
+		}
+		if cmd.Flags().Changed("kind") {
+			req.Flags.Kind = kind
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*SchemaRequest))
+	})(cmd, req)
 }
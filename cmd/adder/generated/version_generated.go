@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: version.md
 
 package generated
 
@@ -20,16 +21,55 @@ func (r *VersionRequest) GetRawArguments() []string {
 // Ensure VersionRequest implements adder.Request interface at compile time
 var _ adder.Request = (*VersionRequest)(nil)
 
+// Ensure VersionRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*VersionRequest)(nil)
+
+// VersionRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var VersionRequestValidateFunc func(req *VersionRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *VersionRequest) Validate() error {
+
+	if VersionRequestValidateFunc != nil {
+		return VersionRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultVersionRequest returns a VersionRequest populated with the declared flag defaults
+func DefaultVersionRequest() *VersionRequest {
+	return &VersionRequest{}
+}
+
+// VersionRequestBuilder builds VersionRequest values, e.g. for testing handlers
+type VersionRequestBuilder struct {
+	req VersionRequest
+}
+
+// NewVersionRequestBuilder creates a builder starting from DefaultVersionRequest
+func NewVersionRequestBuilder() *VersionRequestBuilder {
+	return &VersionRequestBuilder{req: *DefaultVersionRequest()}
+}
+
+// Build returns a copy of the built request
+func (b *VersionRequestBuilder) Build() *VersionRequest {
+	req := b.req
+	return &req
+}
+
 // VersionHandler defines the function type for handling version commands
 type VersionHandler func(cmd *cobra.Command, req *VersionRequest) error
 
 // NewVersionCommand creates a new version command with the provided handler function
-func NewVersionCommand(handler VersionHandler) *cobra.Command {
+func NewVersionCommand(handler VersionHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVersion(cmd, args, handler)
+			return runVersion(cmd, args, handler, options)
 		},
 	}
 
@@ -37,17 +77,31 @@ func NewVersionCommand(handler VersionHandler) *cobra.Command {
 
 	// Register flags
 
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
 	return cmd
 }
 
 // runVersion handles argument and flag extraction
-func runVersion(cmd *cobra.Command, args []string, handler VersionHandler) error {
+func runVersion(cmd *cobra.Command, args []string, handler VersionHandler, options *adder.CommandOptions) error {
 
 	// Create request
-	req := &VersionRequest{
-		RawArguments: args,
+	req := &VersionRequest{}
+
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	if _, err := adder.LoadRequestFile(cmd, req); err != nil {
+		return err
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*VersionRequest))
+	})(cmd, req)
 }
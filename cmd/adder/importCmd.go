@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+// importCmd converts an existing cobra command tree into adder markdown
+func importCmd(cmd *cobra.Command, req *generated.ImportRequest) error {
+	// Load config from --config or the nearest config file
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if req.Flags.BinaryName != "" {
+		config.BinaryName = req.Flags.BinaryName
+	}
+
+	outputDir := config.InputDir
+	if req.Flags.Output != "" {
+		outputDir = req.Flags.Output
+	}
+
+	fmt.Printf("🔍 Importing cobra commands from %s...\n", req.Arguments.Path)
+
+	importer := adder.NewImporter(config)
+	result, err := importer.Import(req.Arguments.Path)
+	if err != nil {
+		return fmt.Errorf("❌ Import failed: %w", err)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+
+	var paths []string
+	if req.Flags.DryRun {
+		files, err := importer.Files(result)
+		if err != nil {
+			return fmt.Errorf("❌ Import failed: %w", err)
+		}
+		for name := range files {
+			paths = append(paths, filepath.Join(outputDir, filepath.FromSlash(name)))
+		}
+		sort.Strings(paths)
+	} else {
+		paths, err = importer.Write(result, outputDir, req.Flags.Force)
+		if err != nil {
+			return fmt.Errorf("❌ Import failed: %w", err)
+		}
+	}
+
+	if req.Flags.DryRun {
+		fmt.Printf("📋 Would import %d commands to %s:\n", result.Count(), outputDir)
+	} else {
+		fmt.Printf("✅ Imported %d commands to %s:\n", result.Count(), outputDir)
+	}
+	for _, path := range paths {
+		fmt.Printf("  - %s\n", path)
+	}
+
+	fmt.Println("\n💡 Next steps:")
+	if config.BinaryName == "" {
+		fmt.Printf("  1. Set binary_name: %s in .adder.yaml\n", result.Root.Name)
+	} else {
+		fmt.Println("  1. Review the warnings and fill in argument descriptions")
+	}
+	fmt.Println("  2. Run adder generate and move the command logic into handlers")
+
+	return nil
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/jrschumacher/adder"
 	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/jrschumacher/adder/cmd/adder/generated/config"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 )
 
 func TestGenerateHandler_HandleGenerate(t *testing.T) {
@@ -86,6 +92,343 @@ This is a test command for CLI integration testing.`
 	}
 }
 
+func TestGenerateHandler_Check(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	markdown := "---\ntitle: Check Command\ncommand:\n  name: check\n---\n"
+	if err := os.WriteFile(filepath.Join(inputDir, "check.md"), []byte(markdown), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	flags := generated.GenerateRequestFlags{
+		BinaryName: "testcli",
+		Input:      inputDir,
+		Output:     outputDir,
+		Package:    "testcli",
+		Suffix:     "_generated.go",
+	}
+	check := func(diff bool) (string, error) {
+		f := flags
+		f.Check = true
+		f.Diff = diff
+		var stdout strings.Builder
+		cmd := &cobra.Command{}
+		cmd.SetOut(&stdout)
+		cmd.SetErr(io.Discard)
+		err := generateCmd(cmd, &generated.GenerateRequest{Flags: f})
+		return stdout.String(), err
+	}
+
+	out, err := check(true)
+	if adder.ExitCode(err) != generated.GenerateOutOfDateExitCode {
+		t.Fatalf("check before generating error = %v, want exit code %d", err, generated.GenerateOutOfDateExitCode)
+	}
+	if !strings.Contains(out, "+++ b/"+filepath.Join(outputDir, "check_generated.go")) {
+		t.Errorf("diff missing new file:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "check_generated.go")); !os.IsNotExist(err) {
+		t.Error("--check must not write generated files")
+	}
+
+	if err := generateCmd(&cobra.Command{}, &generated.GenerateRequest{Flags: flags}); err != nil {
+		t.Fatalf("generateCmd failed: %v", err)
+	}
+	if out, err := check(true); err != nil || out != "" {
+		t.Errorf("check after generating = %q, %v; want no diff and no error", out, err)
+	}
+}
+
+// chdir changes the working directory for the rest of the test
+// Handlers look for the config file from the working directory, so tests can isolate themselves from the repository's
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("restoring working directory: %v", err)
+		}
+	})
+}
+
+func TestGenerateHandler_ConfigDiscovery(t *testing.T) {
+	module := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/app\n",
+		".adder.yaml":         "binary_name: app\ninput: docs\noutput: internal/generated\n",
+		"docs/app.md":         "---\ntitle: App\ncommand:\n  name: app\n---\n",
+		"internal/cli/cli.go": "package cli\n",
+		"other/adder.yaml":    "binary_name: app\ninput: ../docs\noutput: out\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(module, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// go:generate runs adder in the package directory
+	chdir(t, filepath.Join(module, "internal", "cli"))
+	if err := generateCmd(&cobra.Command{}, &generated.GenerateRequest{}); err != nil {
+		t.Fatalf("generateCmd failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(module, "internal", "generated", "app_generated.go")); err != nil {
+		t.Errorf("Expected output relative to the config file: %v", err)
+	}
+
+	// --config names the file explicitly
+	cmd := &cobra.Command{}
+	cmd.Flags().String("config", "", "")
+	if err := cmd.Flags().Set("config", filepath.Join(module, "other", "adder.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := generateCmd(cmd, &generated.GenerateRequest{}); err != nil {
+		t.Fatalf("generateCmd with --config failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(module, "other", "out", "app_generated.go")); err != nil {
+		t.Errorf("Expected output relative to the --config file: %v", err)
+	}
+
+	// A missing --config file is an error rather than a silent fallback
+	if err := cmd.Flags().Set("config", filepath.Join(module, "missing.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := generateCmd(cmd, &generated.GenerateRequest{}); err == nil {
+		t.Error("Expected an error for a missing --config file")
+	}
+}
+
+func TestGenerateHandler_Targets(t *testing.T) {
+	module := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/mono\n",
+		".adder.yaml":           "targets:\n  - name: server\n    binary_name: server\n    input: server/docs\n    output: server/generated\n  - name: agent\n    binary_name: agent\n    input: agent/docs\n    output: agent/generated\n",
+		"server/docs/server.md": "---\ntitle: Server\ncommand:\n  name: server\n---\n",
+		"agent/docs/agent.md":   "---\ntitle: Agent\ncommand:\n  name: agent\n---\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(module, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, module)
+
+	serverFile := filepath.Join(module, "server", "generated", "server_generated.go")
+	agentFile := filepath.Join(module, "agent", "generated", "agent_generated.go")
+
+	// --target generates a single target
+	req := &generated.GenerateRequest{Flags: generated.GenerateRequestFlags{Target: "agent"}}
+	if err := generateCmd(&cobra.Command{}, req); err != nil {
+		t.Fatalf("generateCmd --target failed: %v", err)
+	}
+	if _, err := os.Stat(agentFile); err != nil {
+		t.Errorf("Expected the agent target to be generated: %v", err)
+	}
+	if _, err := os.Stat(serverFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the server target to be skipped, stat error = %v", err)
+	}
+
+	// Without --target every target is generated
+	if err := generateCmd(&cobra.Command{}, &generated.GenerateRequest{}); err != nil {
+		t.Fatalf("generateCmd failed: %v", err)
+	}
+	if _, err := os.Stat(serverFile); err != nil {
+		t.Errorf("Expected the server target to be generated: %v", err)
+	}
+
+	// An output flag would make every target write to one directory
+	req = &generated.GenerateRequest{Flags: generated.GenerateRequestFlags{Output: "shared"}}
+	if err := generateCmd(&cobra.Command{}, req); err == nil || !strings.Contains(err.Error(), "overlapping outputs") {
+		t.Errorf("generateCmd --output error = %v, want overlapping outputs", err)
+	}
+
+	req = &generated.GenerateRequest{Flags: generated.GenerateRequestFlags{Target: "missing"}}
+	if err := generateCmd(&cobra.Command{}, req); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+}
+
+func TestGenerateHandler_Precedence(t *testing.T) {
+	module := t.TempDir()
+	files := map[string]string{
+		"go.mod":               "module example.com/app\n",
+		".adder.yaml":          "binary_name: app\ninput: other\noutput: from-file\n",
+		"docs/commands/app.md": "---\ntitle: App\ncommand:\n  name: app\n---\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(module, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, module)
+	t.Setenv("ADDER_OUTPUT", "from-env")
+
+	// --input with its default value still overrides the config file
+	cmd := generated.NewGenerateCommand(generateCmd)
+	cmd.SetArgs([]string{"--input", "docs/commands"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Generate command failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(module, "from-env", "app_generated.go")); err != nil {
+		t.Errorf("Expected the environment to override the config file output: %v", err)
+	}
+
+	// Flags override the environment
+	cmd = generated.NewGenerateCommand(generateCmd)
+	cmd.SetArgs([]string{"--input", "docs/commands", "--output", "from-flag"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Generate command failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(module, "from-flag", "app_generated.go")); err != nil {
+		t.Errorf("Expected the flag to override the environment: %v", err)
+	}
+}
+
+func TestConfigHandler(t *testing.T) {
+	module := t.TempDir()
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, ".adder.yaml"), []byte("binary_name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, module)
+	t.Setenv("ADDER_PACKAGE", "cli")
+
+	values, err := configCmd(&cobra.Command{}, &generated.ConfigRequest{})
+	if err != nil {
+		t.Fatalf("configCmd failed: %v", err)
+	}
+
+	got := make(map[string]generated.ConfigResponse)
+	for _, value := range values {
+		got[value.Key] = value
+	}
+	want := map[string]generated.ConfigResponse{
+		"binary_name":  {Key: "binary_name", Value: "app", Source: ".adder.yaml"},
+		"package":      {Key: "package", Value: "cli", Source: "ADDER_PACKAGE"},
+		"index_format": {Key: "index_format", Value: "directory", Source: adder.SourceDefault},
+	}
+	for key, w := range want {
+		if got[key] != w {
+			t.Errorf("%s = %+v, want %+v", key, got[key], w)
+		}
+	}
+	if len(values) != len(adder.ConfigKeys) {
+		t.Errorf("configCmd returned %d values, want one per key", len(values))
+	}
+}
+
+func TestConfigMigrateHandler(t *testing.T) {
+	module := t.TempDir()
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(module, ".adder.yaml")
+	original := "binary_name: app\nsuffix: _gen.go\n"
+	if err := os.WriteFile(configFile, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, module)
+
+	// A dry run leaves the file alone
+	req := &config.MigrateRequest{}
+	req.Flags.DryRun = true
+	if err := configMigrateCmd(&cobra.Command{}, req); err != nil {
+		t.Fatalf("configMigrateCmd failed: %v", err)
+	}
+	if content, _ := os.ReadFile(configFile); string(content) != original {
+		t.Errorf("Dry run changed the config file:\n%s", content)
+	}
+
+	if err := configMigrateCmd(&cobra.Command{}, &config.MigrateRequest{}); err != nil {
+		t.Fatalf("configMigrateCmd failed: %v", err)
+	}
+	if content, _ := os.ReadFile(configFile); string(content) != "binary_name: app\ngenerated_file_suffix: _gen.go\n" {
+		t.Errorf("Config file not migrated:\n%s", content)
+	}
+	loaded, err := loadConfig(&cobra.Command{})
+	if err != nil || len(loaded.Warnings()) != 0 {
+		t.Errorf("Migrated config should load without warnings: %v, %v", loaded.Warnings(), err)
+	}
+
+	// Unknown keys are errors rather than silently ignored
+	if err := os.WriteFile(configFile, []byte("binary_name: app\nouptut: out\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(&cobra.Command{}); err == nil || !strings.Contains(err.Error(), `unknown config key "ouptut"`) {
+		t.Errorf("Expected an unknown key error, got %v", err)
+	}
+}
+
+func TestImportHandler_HandleImport(t *testing.T) {
+	srcDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	source := `package cmd
+
+import "github.com/spf13/cobra"
+
+var rootCmd = &cobra.Command{Use: "legacy", Short: "A legacy CLI"}
+
+var syncCmd = &cobra.Command{Use: "sync <target>", Short: "Sync a target"}
+
+func init() {
+	syncCmd.Flags().BoolP("dry-run", "n", false, "Show what would change")
+	rootCmd.AddCommand(syncCmd)
+}
+`
+	if err := os.WriteFile(filepath.Join(srcDir, "root.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	// Without a config file the binary name is detected from the source
+	chdir(t, t.TempDir())
+
+	req := &generated.ImportRequest{
+		Arguments: generated.ImportRequestArguments{Path: srcDir},
+		Flags:     generated.ImportRequestFlags{Output: outputDir},
+	}
+	if err := importCmd(&cobra.Command{}, req); err != nil {
+		t.Fatalf("importCmd failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "sync.md"))
+	if err != nil {
+		t.Fatalf("Expected sync.md to be written: %v", err)
+	}
+	for _, want := range []string{"name: sync", "name: target", "name: dry-run", `shorthand: "n"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("sync.md missing %q:\n%s", want, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "legacy.md")); err != nil {
+		t.Errorf("Expected legacy.md to be written: %v", err)
+	}
+
+	// Existing files are kept unless --force is given
+	if err := importCmd(&cobra.Command{}, req); err == nil {
+		t.Error("Expected an error when imported files already exist")
+	}
+}
+
 func TestVersionHandler_HandleVersion(t *testing.T) {
 	// Create request
 	req := &generated.VersionRequest{}
@@ -103,6 +446,98 @@ func TestVersionHandler_HandleVersion(t *testing.T) {
 	// For now, we just verify it doesn't error
 }
 
+func TestSchemaFor_FrontmatterKeys(t *testing.T) {
+	data, err := schemaFor("command")
+	if err != nil {
+		t.Fatalf("schemaFor() failed: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	// Frontmatter using every key added after the original schema
+	frontmatter := `
+title: Delete a user
+command:
+  name: delete [name]
+  setup: true
+  destructive: true
+  confirm: Delete {{.Arguments.Name}}?
+  arguments:
+    - name: name
+      required: true
+      prompt: User name
+  flags:
+    - name: password
+      type: string
+      prompt: true
+      sensitive: true
+  output:
+    format: json
+    list: true
+    fields:
+      - name: id
+        type: int
+    columns: [id]
+  errors:
+    - name: not_found
+      code: 4
+      description: The user does not exist
+`
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		t.Fatalf("yaml.Unmarshal() failed: %v", err)
+	}
+	checkSchemaKeys(t, schema, schema, doc, "")
+
+	// exit_codes is accepted as an alias of errors
+	aliased := map[interface{}]interface{}{
+		"title": "Delete a user",
+		"command": map[interface{}]interface{}{
+			"name":       "delete",
+			"exit_codes": []interface{}{map[interface{}]interface{}{"name": "not_found", "code": 4}},
+		},
+	}
+	checkSchemaKeys(t, schema, schema, aliased, "")
+}
+
+// checkSchemaKeys reports keys of value that schema does not allow and required keys it misses
+func checkSchemaKeys(t *testing.T, root, schema map[string]interface{}, value interface{}, path string) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		defs := root["$defs"].(map[string]interface{})
+		schema = defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	}
+
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, item := range v {
+			property, ok := properties[key.(string)].(map[string]interface{})
+			if !ok {
+				t.Errorf("%s%v is not allowed by the schema", path, key)
+				continue
+			}
+			checkSchemaKeys(t, root, property, item, fmt.Sprintf("%s%v.", path, key))
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := v[key]; !ok {
+				t.Errorf("%s%v is required by the schema", path, key)
+			}
+		}
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range v {
+			checkSchemaKeys(t, root, items, item, fmt.Sprintf("%s%d.", path, i))
+		}
+	}
+}
+
 func TestCLI_EndToEnd(t *testing.T) {
 	// Create temporary directories
 	tempDir, err := os.MkdirTemp("", "adder-e2e-test-*")
@@ -208,6 +643,77 @@ A complex test command with arguments and flags.`,
 	}
 }
 
+func TestCLI_FromFile(t *testing.T) {
+	tempDir := t.TempDir()
+	inputDir := filepath.Join(tempDir, "docs")
+	outputDir := filepath.Join(tempDir, "generated")
+
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input dir: %v", err)
+	}
+
+	simpleDoc := `---
+title: Simple Command
+command:
+  name: simple
+---`
+	if err := os.WriteFile(filepath.Join(inputDir, "simple.md"), []byte(simpleDoc), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Request file provides every flag, the explicit --package flag overrides it
+	requestFile := filepath.Join(tempDir, "generate.yaml")
+	request := "flags:\n" +
+		"  binaryName: fromfile\n" +
+		"  input: " + inputDir + "\n" +
+		"  output: " + outputDir + "\n" +
+		"  pkg: fromfile\n"
+	if err := os.WriteFile(requestFile, []byte(request), 0644); err != nil {
+		t.Fatalf("Failed to write request file: %v", err)
+	}
+
+	generateCmd := generated.NewGenerateCommand(generateCmd)
+	generateCmd.SetArgs([]string{"--from-file", requestFile, "--package", "override"})
+
+	if err := generateCmd.Execute(); err != nil {
+		t.Fatalf("Generate command failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "simple_generated.go"))
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "package override") {
+		t.Errorf("Explicit flag did not override request file package")
+	}
+}
+
+func TestCLI_Middleware(t *testing.T) {
+	var seen string
+	audit := func(next adder.HandlerFunc) adder.HandlerFunc {
+		return func(cmd *cobra.Command, req adder.Request) error {
+			seen = cmd.CommandPath()
+			return next(cmd, req)
+		}
+	}
+
+	rootCmd := generated.NewAdderCommand(adderCmd)
+	rootCmd.AddCommand(generated.NewVersionCommand(func(cmd *cobra.Command, req *generated.VersionRequest) error {
+		panic("unexpected")
+	}, adder.WithMiddleware(audit, adder.Recover())))
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs([]string{"version"})
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "panic: unexpected") {
+		t.Errorf("Expected recovered panic, got %v", err)
+	}
+	if seen != "adder version" {
+		t.Errorf("Middleware saw command %q, want %q", seen, "adder version")
+	}
+}
+
 func TestCLI_ErrorHandling(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
+	"github.com/jrschumacher/adder"
 	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/jrschumacher/adder/cmd/adder/generated/config"
 	"github.com/spf13/cobra"
 )
 
@@ -33,11 +37,111 @@ It processes markdown files with YAML frontmatter to create:
 	rootCmd.AddCommand(generated.NewVersionCommand(versionCmd))
 	rootCmd.AddCommand(generated.NewInitCommand(initCmd))
 	rootCmd.AddCommand(generated.NewSchemaCommand(schemaCmd))
+	rootCmd.AddCommand(generated.NewImportCommand(importCmd))
+	rootCmd.AddCommand(generated.NewDocsCommand(docsCmd))
+	rootCmd.AddCommand(generated.NewManCommand(manCmd))
+	rootCmd.AddCommand(generated.NewCleanCommand(cleanCmd))
+	configCommand := generated.NewConfigCommand(configCmd)
+	configCommand.AddCommand(config.NewMigrateCommand(configMigrateCmd))
+	rootCmd.AddCommand(configCommand)
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Ctrl-C cancels long running work such as generating a large tree
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	rootCmd.SetContext(ctx)
+
+	// Exit with the code of the returned error (e.g. 2 for usage errors)
+	code := adder.Execute(rootCmd)
+	stop()
+	os.Exit(code)
+}
+
+// commandContext returns the context of a running command, or a background context
+// for commands invoked directly, e.g. in tests
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// loadConfig loads the file named by --config or ADDER_CONFIG, or the nearest config file,
+// and applies the ADDER_* environment overrides
+// An invalid config file, e.g. with an unknown key, is an error; deprecated keys are reported on stderr.
+func loadConfig(cmd *cobra.Command) (*adder.Config, error) {
+	path, err := configPath(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	config := adder.DefaultConfig()
+	if path != "" {
+		config, err = adder.LoadConfigFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Loading config failed: %w", err)
+		}
+	}
+	for _, warning := range config.Warnings() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  %s\n", warning)
+	}
+
+	if err := config.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, fmt.Errorf("❌ Loading config failed: %w", err)
+	}
+	return config, nil
+}
+
+// configPath returns the config file named by --config or ADDER_CONFIG, or the nearest one
+// It returns "" when there is no config file.
+func configPath(cmd *cobra.Command) (string, error) {
+	if path := namedConfigPath(cmd); path != "" {
+		return path, nil
+	}
+
+	path, err := adder.FindConfigFile(".")
+	if err != nil {
+		return "", fmt.Errorf("❌ Finding config file failed: %w", err)
+	}
+	return path, nil
+}
+
+// namedConfigPath returns the config file named by --config or ADDER_CONFIG
+func namedConfigPath(cmd *cobra.Command) string {
+	// Handlers invoked directly, e.g. in tests, have no --config flag
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		return path
+	}
+	return os.Getenv(configEnv)
+}
+
+// configEnv names the config file when --config is not given
+const configEnv = adder.EnvPrefix + "CONFIG"
+
+// flagOverride is a config value a command line flag can set
+type flagOverride struct {
+	flag  string // Flag name, e.g. "input"
+	key   string // Config key, e.g. "input"
+	value string // Value of the flag in the request
+}
+
+// applyFlags returns a copy of config with the values of the flags given on the command line
+// Flags win over the environment and the config file, even when given with their default value.
+// A value that differs from the flag default was given too, e.g. by --from-file.
+// Handlers invoked directly, e.g. in tests, have no parsed flags, so non-empty values count as given.
+func applyFlags(cmd *cobra.Command, config *adder.Config, overrides ...flagOverride) (*adder.Config, error) {
+	config = config.Clone()
+	for _, override := range overrides {
+		given := override.value != ""
+		if flag := cmd.Flags().Lookup(override.flag); flag != nil {
+			given = flag.Changed || override.value != flag.DefValue
+		}
+		if !given {
+			continue
+		}
+		if err := config.Set(override.key, override.value, "--"+override.flag); err != nil {
+			return nil, fmt.Errorf("❌ --%s: %w", override.flag, err)
+		}
 	}
+	return config, nil
 }
 
 // adderCmd processes the root adder command request
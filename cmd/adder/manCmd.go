@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jrschumacher/adder"
+	"github.com/jrschumacher/adder/cmd/adder/generated"
+	"github.com/spf13/cobra"
+)
+
+// manCmd writes roff man pages for the parsed command tree
+func manCmd(cmd *cobra.Command, req *generated.ManRequest) error {
+	// Load config from --config or the nearest config file
+	fileConfig, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Merge command line flags with config file (flags take precedence)
+	config, err := applyFlags(cmd, fileConfig,
+		flagOverride{"binary-name", "binary_name", req.Flags.BinaryName},
+		flagOverride{"input", "input", req.Flags.Input},
+	)
+	if err != nil {
+		return err
+	}
+	if config.BinaryName == "" {
+		return fmt.Errorf("binary_name is required. Set it in .adder.yaml or use --binary-name flag")
+	}
+
+	date, err := manDate()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📖 Generating man pages from %s to %s...\n", config.InputDir, req.Flags.Output)
+
+	tree, err := adder.New(config).CommandTree()
+	if err != nil {
+		return fmt.Errorf("❌ Parsing failed: %w", err)
+	}
+
+	paths, err := adder.WriteManPages(tree, req.Flags.Output, adder.ManOptions{
+		Date:   date,
+		Source: req.Flags.Source,
+		Manual: req.Flags.Manual,
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Man page generation failed: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %d man pages:\n", len(paths))
+	for _, path := range paths {
+		fmt.Printf("  - %s\n", path)
+	}
+
+	return nil
+}
+
+// manDate returns the page date, honoring SOURCE_DATE_EPOCH for reproducible builds
+func manDate() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
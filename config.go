@@ -8,40 +8,125 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-// LoadConfig loads configuration from file and merges with defaults
+// LoadConfig finds the config file for dir and loads it merged with defaults
+// The search starts in dir and walks up to the directory containing go.mod, so commands run
+// from a package directory, e.g. by go:generate, use the config file of the module.
+// Without a config file the defaults are returned.
 func LoadConfig(dir string) (*Config, error) {
-	config := DefaultConfig()
-
-	// Look for config file in the specified directory
-	configPath, err := findConfigFile(dir)
+	// Look for config file in the specified directory and its parents
+	configPath, err := FindConfigFile(dir)
 	if err != nil {
 		return nil, fmt.Errorf("error finding config file: %w", err)
 	}
 
 	// If no config file found, return defaults
 	if configPath == "" {
-		return config, nil
+		return DefaultConfig(), nil
 	}
 
+	return LoadConfigFile(configPath)
+}
+
+// LoadConfigFile loads the config file at configPath merged with defaults
+// Relative directories in the file, including the default ones, are resolved against the directory of the file
+func LoadConfigFile(configPath string) (*Config, error) {
+	config := DefaultConfig()
+
 	// Read config file
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading config file %s: %w", configPath, err)
 	}
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, config); err != nil {
+	// Check the keys, accepting deprecated ones under their new names
+	var keys yaml.MapSlice
+	if err := yaml.Unmarshal(data, &keys); err != nil {
 		return nil, fmt.Errorf("parsing config file %s: %w", configPath, err)
 	}
+	keys, warnings, err := normalizeConfigKeys(keys, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", configPath, err)
+	}
+	if len(warnings) > 0 {
+		if data, err = yaml.Marshal(keys); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", configPath, err)
+		}
+	}
 
-	// Validate required fields
-	if config.BinaryName == "" {
+	// Parse YAML
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", configPath, err)
+	}
+	config.warnings = warnings
+
+	// Record which values the file sets
+	config.setFileSources(keys, configPath)
+
+	// Targets share the settings above and require their own binary_name instead
+	if len(config.Targets) > 0 {
+		targets, err := parseTargets(data, config, configPath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", configPath, err)
+		}
+		config.Targets = targets
+	} else if config.BinaryName == "" {
+		// Validate required fields
 		return nil, fmt.Errorf("binary_name is required in config file %s", configPath)
 	}
 
+	configDir := filepath.Dir(configPath)
+	for _, c := range append([]*Config{config}, config.Targets...) {
+		for _, dir := range []*string{&c.InputDir, &c.OutputDir, &c.TemplatesDir} {
+			if *dir != "" && !filepath.IsAbs(*dir) {
+				*dir = filepath.Join(configDir, *dir)
+			}
+		}
+	}
+
+	if err := CheckTargetOutputs(config.Targets); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", configPath, err)
+	}
+
 	return config, nil
 }
 
+// FindConfigFile looks for .adder.yaml or .adder.yml in dir and its parents
+// The search stops at the first directory containing go.mod, the root of the module.
+// The returned path is relative when dir is, e.g. ../../.adder.yaml, and "" when there is no config file.
+func FindConfigFile(dir string) (string, error) {
+	start, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for current := start; ; {
+		configPath, err := findConfigFile(current)
+		if err != nil {
+			return "", err
+		}
+		if configPath != "" {
+			if filepath.IsAbs(dir) {
+				return configPath, nil
+			}
+			rel, err := filepath.Rel(start, configPath)
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, rel), nil
+		}
+
+		// The module root is as far up as a config file can apply
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", nil
+		}
+		current = parent
+	}
+}
+
 // findConfigFile looks for .adder.yaml or .adder.yml in the given directory
 func findConfigFile(dir string) (string, error) {
 	// Check .adder.yaml first
@@ -81,34 +166,34 @@ func SaveConfig(config *Config, path string) error {
 }
 
 // MergeWithFlags merges command line flags into config, with flags taking precedence
+// Flags equal to their default are ignored, so they cannot override a config file.
+//
+// Deprecated: Use Config.Set for the flags the user changed, e.g. as reported by cobra's Flag.Changed.
 func MergeWithFlags(config *Config, binaryName, input, output, pkg, suffix string) *Config {
-	merged := &Config{
-		BinaryName:          config.BinaryName,
-		InputDir:            config.InputDir,
-		OutputDir:           config.OutputDir,
-		Package:             config.Package,
-		GeneratedFileSuffix: config.GeneratedFileSuffix,
-		IndexFormat:         config.IndexFormat,
-		PackageStrategy:     config.PackageStrategy,
-		Validation:          config.Validation,
-	}
+	merged := config.Clone()
+	merged.Targets = nil
 
 	// Override with flags if provided
 	if binaryName != "" {
-		merged.BinaryName = binaryName
+		merged.setFlag("binary_name", binaryName)
 	}
 	if input != "" && input != "docs/commands" { // Check against default
-		merged.InputDir = input
+		merged.setFlag("input", input)
 	}
 	if output != "" && output != "generated" { // Check against default
-		merged.OutputDir = output
+		merged.setFlag("output", output)
 	}
 	if pkg != "" && pkg != "generated" { // Check against default
-		merged.Package = pkg
+		merged.setFlag("package", pkg)
 	}
 	if suffix != "" && suffix != "_generated.go" { // Check against default
-		merged.GeneratedFileSuffix = suffix
+		merged.setFlag("generated_file_suffix", suffix)
 	}
 
 	return merged
-}
\ No newline at end of file
+}
+
+// setFlag sets a string value given as a flag
+func (c *Config) setFlag(key, value string) {
+	_ = c.Set(key, value, "flag")
+}
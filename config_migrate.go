@@ -0,0 +1,226 @@
+package adder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DeprecatedConfigKeys maps config keys that are still accepted to the keys replacing them
+// Loading a config file with one of them warns; adder config migrate rewrites them
+var DeprecatedConfigKeys = map[string]string{
+	"suffix":              "generated_file_suffix",
+	"root_command_format": "index_format",
+}
+
+// configKeyValues lists the accepted values of config keys with a fixed set of them
+var configKeyValues = map[string][]string{
+	"index_format":     {"directory", "index", "_index", "hugo"},
+	"package_strategy": {"single", "directory", "path"},
+}
+
+// normalizeConfigKeys renames the deprecated keys of a config file mapping and rejects unknown ones
+// It returns a warning for each deprecated key; source names the file in the warnings
+func normalizeConfigKeys(item yaml.MapSlice, source string) (yaml.MapSlice, []string, error) {
+	normalized, warnings, err := normalizeMapping(item, "", false, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, entry := range normalized {
+		if entry.Key != "targets" {
+			continue
+		}
+		list, ok := entry.Value.([]interface{})
+		if !ok {
+			if entry.Value == nil {
+				continue
+			}
+			return nil, nil, fmt.Errorf("targets must be a list")
+		}
+		targets := make([]interface{}, len(list))
+		for j, value := range list {
+			target, ok := value.(yaml.MapSlice)
+			if !ok {
+				return nil, nil, fmt.Errorf("target %d: must be a mapping", j+1)
+			}
+			where := fmt.Sprintf("target %d", j+1)
+			if name, ok := mappingValue(target, "name"); ok {
+				where = fmt.Sprintf("target %v", name)
+			}
+			target, targetWarnings, err := normalizeMapping(target, "", true, fmt.Sprintf("%s (%s)", source, where))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", where, err)
+			}
+			targets[j] = target
+			warnings = append(warnings, targetWarnings...)
+		}
+		normalized[i].Value = targets
+	}
+
+	return normalized, warnings, nil
+}
+
+// normalizeMapping checks the keys of one mapping; nested mappings, e.g. validation, use prefix
+func normalizeMapping(item yaml.MapSlice, prefix string, target bool, source string) (yaml.MapSlice, []string, error) {
+	var warnings []string
+	normalized := make(yaml.MapSlice, 0, len(item))
+	seen := make(map[string]string)
+
+	for _, entry := range item {
+		key := fmt.Sprint(entry.Key)
+		original := key
+		if replacement, ok := DeprecatedConfigKeys[key]; ok && prefix == "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s is deprecated, use %s (adder config migrate rewrites it)", source, key, replacement))
+			entry.Key = replacement
+			key = replacement
+		}
+		if previous, ok := seen[key]; ok {
+			if previous == original {
+				return nil, nil, fmt.Errorf("%s%s is set more than once", prefix, original)
+			}
+			return nil, nil, fmt.Errorf("%s and %s are both set", previous, original)
+		}
+		seen[key] = original
+
+		switch {
+		case prefix == "" && key == "targets":
+			if target {
+				return nil, nil, fmt.Errorf("targets cannot be nested")
+			}
+		case prefix == "" && key == "name" && target:
+		case isConfigKey(prefix + key):
+			if err := checkConfigValue(prefix+key, entry.Value); err != nil {
+				return nil, nil, err
+			}
+		case isConfigSection(prefix + key):
+			nested, ok := entry.Value.(yaml.MapSlice)
+			if !ok {
+				if entry.Value == nil {
+					break
+				}
+				return nil, nil, fmt.Errorf("%s%s must be a mapping", prefix, key)
+			}
+			value, _, err := normalizeMapping(nested, prefix+key+".", target, source)
+			if err != nil {
+				return nil, nil, err
+			}
+			entry.Value = value
+		default:
+			return nil, nil, fmt.Errorf("unknown config key %q (valid: %s)", prefix+key, strings.Join(validConfigKeys(prefix, target), ", "))
+		}
+		normalized = append(normalized, entry)
+	}
+
+	return normalized, warnings, nil
+}
+
+// isConfigKey reports whether key is one of ConfigKeys
+func isConfigKey(key string) bool {
+	for _, k := range ConfigKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigSection reports whether key is a mapping of config keys, e.g. validation
+func isConfigSection(key string) bool {
+	for _, k := range ConfigKeys {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// validConfigKeys returns the keys accepted in a mapping, for error messages
+func validConfigKeys(prefix string, target bool) []string {
+	keys := make(map[string]bool)
+	for _, k := range ConfigKeys {
+		if rest := strings.TrimPrefix(k, prefix); rest != k || prefix == "" {
+			keys[prefix+strings.SplitN(rest, ".", 2)[0]] = true
+		}
+	}
+	if prefix == "" {
+		if target {
+			keys["name"] = true
+		} else {
+			keys["targets"] = true
+		}
+	}
+
+	valid := make([]string, 0, len(keys))
+	for k := range keys {
+		valid = append(valid, k)
+	}
+	sort.Strings(valid)
+	return valid
+}
+
+// checkConfigValue rejects values outside the accepted set of a config key
+func checkConfigValue(key string, value interface{}) error {
+	accepted, ok := configKeyValues[key]
+	if !ok || value == nil {
+		return nil
+	}
+	for _, v := range accepted {
+		if fmt.Sprint(value) == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %q is not one of %s", key, fmt.Sprint(value), strings.Join(accepted, ", "))
+}
+
+// mappingValue returns the value of key in a YAML mapping
+func mappingValue(item yaml.MapSlice, key string) (interface{}, bool) {
+	for _, entry := range item {
+		if fmt.Sprint(entry.Key) == key {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// deprecatedKeyPattern matches a deprecated key at the start of a line of a config file,
+// including the first key of a target, e.g. "  - root_command_format: hugo"
+var deprecatedKeyPattern = func() *regexp.Regexp {
+	keys := make([]string, 0, len(DeprecatedConfigKeys))
+	for key := range DeprecatedConfigKeys {
+		keys = append(keys, regexp.QuoteMeta(key))
+	}
+	sort.Strings(keys)
+	return regexp.MustCompile(`^(\s*(?:-\s+)?)(` + strings.Join(keys, "|") + `)(\s*:)`)
+}()
+
+// MigrateConfig rewrites the deprecated keys of a config file to the keys replacing them
+// Only the keys change, so comments and formatting are kept. It returns the rewritten file
+// and a description of each change; a file with unknown keys is not migrated.
+func MigrateConfig(data []byte) ([]byte, []string, error) {
+	// Validate the file first, so conflicting or unknown keys are reported instead of rewritten
+	var item yaml.MapSlice
+	if err := yaml.Unmarshal(data, &item); err != nil {
+		return nil, nil, err
+	}
+	if _, _, err := normalizeConfigKeys(item, ""); err != nil {
+		return nil, nil, err
+	}
+
+	var changes []string
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		match := deprecatedKeyPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		replacement := DeprecatedConfigKeys[match[2]]
+		lines[i] = match[1] + replacement + match[3] + line[len(match[0]):]
+		changes = append(changes, fmt.Sprintf("line %d: %s → %s", i+1, match[2], replacement))
+	}
+
+	return []byte(strings.Join(lines, "")), changes, nil
+}
@@ -0,0 +1,145 @@
+package adder
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigFile_DeprecatedKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".adder.yaml")
+	writeTestFile(t, path, `binary_name: app
+suffix: _gen.go
+targets:
+  - name: api
+    binary_name: api
+    output: api
+    root_command_format: hugo
+`)
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if config.GeneratedFileSuffix != "_gen.go" || config.Source("generated_file_suffix") != path {
+		t.Errorf("suffix should set generated_file_suffix, got %q from %s", config.GeneratedFileSuffix, config.Source("generated_file_suffix"))
+	}
+	if target := config.Targets[0]; target.IndexFormat != "hugo" || target.GeneratedFileSuffix != "_gen.go" {
+		t.Errorf("target = %+v, want index_format hugo and the shared suffix", target)
+	}
+
+	warnings := config.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("Warnings() = %v, want 2", warnings)
+	}
+	for i, want := range []string{"suffix is deprecated, use generated_file_suffix", "(target api): root_command_format is deprecated"} {
+		if !strings.Contains(warnings[i], want) {
+			t.Errorf("Warnings()[%d] = %q, want it to contain %q", i, warnings[i], want)
+		}
+	}
+}
+
+func TestLoadConfigFile_InvalidKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "binary_name: app\nsufix: _gen.go\n", `unknown config key "sufix"`},
+		{"unknown nested key", "binary_name: app\nvalidation:\n  stric: true\n", `unknown config key "validation.stric"`},
+		{"unknown target key", "targets:\n  - name: api\n    binary_name: api\n    ouput: api\n", `target api: unknown config key "ouput"`},
+		{"name outside targets", "name: app\nbinary_name: app\n", `unknown config key "name"`},
+		{"deprecated and new key", "binary_name: app\nsuffix: _gen.go\ngenerated_file_suffix: _x.go\n", "suffix and generated_file_suffix are both set"},
+		{"repeated key", "binary_name: app\noutput: a\noutput: b\n", "output is set more than once"},
+		{"invalid value", "binary_name: app\nindex_format: dir\n", `index_format: "dir" is not one of`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".adder.yaml")
+			writeTestFile(t, path, tt.content)
+
+			_, err := LoadConfigFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	data := []byte(`# Adder configuration file
+binary_name: app
+suffix: _gen.go # keep generated files short
+targets:
+  - root_command_format: hugo
+    name: docs
+    binary_name: docs
+`)
+
+	migrated, changes, err := MigrateConfig(data)
+	if err != nil {
+		t.Fatalf("MigrateConfig() error = %v", err)
+	}
+	want := `# Adder configuration file
+binary_name: app
+generated_file_suffix: _gen.go # keep generated files short
+targets:
+  - index_format: hugo
+    name: docs
+    binary_name: docs
+`
+	if string(migrated) != want {
+		t.Errorf("MigrateConfig() =\n%s\nwant\n%s", migrated, want)
+	}
+	wantChanges := []string{"line 3: suffix → generated_file_suffix", "line 5: root_command_format → index_format"}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes = %v, want %v", changes, wantChanges)
+	}
+
+	// Migrating again changes nothing
+	if again, changes, err := MigrateConfig(migrated); err != nil || len(changes) != 0 || string(again) != want {
+		t.Errorf("MigrateConfig() of a migrated file = %v, %v", changes, err)
+	}
+
+	if _, _, err := MigrateConfig([]byte("binary_name: app\nsufix: _gen.go\n")); err == nil {
+		t.Error("MigrateConfig() expected an error for an unknown key")
+	}
+}
+
+func TestGenerateConfigJSONSchema(t *testing.T) {
+	data, err := GenerateConfigJSONSchema()
+	if err != nil {
+		t.Fatalf("GenerateConfigJSONSchema() error = %v", err)
+	}
+	var schema struct {
+		Defs map[string]struct {
+			Properties           map[string]json.RawMessage `json:"properties"`
+			AdditionalProperties bool                       `json:"additionalProperties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	// The schema accepts the same keys as LoadConfigFile
+	file := schema.Defs["ConfigFileSchema"]
+	var got []string
+	for key := range file.Properties {
+		got = append(got, key)
+	}
+	sort.Strings(got)
+	if want := validConfigKeys("", false); !reflect.DeepEqual(got, want) {
+		t.Errorf("schema properties = %v, want %v", got, want)
+	}
+	if file.AdditionalProperties {
+		t.Error("schema should reject unknown keys")
+	}
+	if _, ok := schema.Defs["TargetSchema"].Properties["name"]; !ok {
+		t.Error("targets should have a name")
+	}
+}
@@ -0,0 +1,55 @@
+package adder
+
+import (
+	"encoding/json"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ConfigSettings are the settings of a config file that targets can override
+type ConfigSettings struct {
+	BinaryName          string            `json:"binary_name,omitempty" jsonschema:"title=Binary Name,description=Name of the CLI binary; its markdown file is the root command"`
+	Input               string            `json:"input,omitempty" jsonschema:"title=Input Directory,description=Directory of the command markdown files,default=docs/commands"`
+	Output              string            `json:"output,omitempty" jsonschema:"title=Output Directory,description=Directory the generated code is written to,default=generated"`
+	Package             string            `json:"package,omitempty" jsonschema:"title=Package,description=Go package of the generated root commands,default=generated"`
+	GeneratedFileSuffix string            `json:"generated_file_suffix,omitempty" jsonschema:"title=Generated File Suffix,description=Suffix of generated file names (replaces suffix),default=_generated.go"`
+	IndexFormat         string            `json:"index_format,omitempty" jsonschema:"title=Index Format,description=Name of the file defining a command group (replaces root_command_format),enum=directory,enum=index,enum=_index,enum=hugo,default=directory"`
+	PackageStrategy     string            `json:"package_strategy,omitempty" jsonschema:"title=Package Strategy,description=How subcommand package names are derived,enum=single,enum=directory,enum=path,default=directory"`
+	Templates           string            `json:"templates,omitempty" jsonschema:"title=Templates Directory,description=Directory of *.tmpl files overriding or extending the built-in templates"`
+	Validation          *ValidationSchema `json:"validation,omitempty" jsonschema:"title=Validation,description=Validation settings"`
+}
+
+// ValidationSchema defines the validation settings of a config file
+type ValidationSchema struct {
+	Strict bool `json:"strict,omitempty" jsonschema:"title=Strict,description=Treat validation warnings as errors"`
+}
+
+// ConfigFileSchema defines the complete schema for .adder.yaml
+type ConfigFileSchema struct {
+	ConfigSettings
+	Targets []TargetSchema `json:"targets,omitempty" jsonschema:"title=Targets,description=CLIs generated from one config file; the settings above are their defaults"`
+}
+
+// TargetSchema defines one entry of the targets of a config file
+type TargetSchema struct {
+	Name string `json:"name" jsonschema:"title=Target Name,description=Name used to select the target with --target,required"`
+	ConfigSettings
+}
+
+// GenerateConfigJSONSchema generates a JSON Schema for the config file from the ConfigFileSchema struct
+// Deprecated keys are left out, so editors flag them; adder config migrate rewrites them
+func GenerateConfigJSONSchema() ([]byte, error) {
+	reflector := jsonschema.Reflector{
+		AllowAdditionalProperties:  false,
+		RequiredFromJSONSchemaTags: true,
+	}
+
+	schema := reflector.Reflect(&ConfigFileSchema{})
+
+	// Add custom schema properties
+	schema.Title = "Adder Config Schema"
+	schema.Description = "JSON Schema for validating the adder config file .adder.yaml"
+	schema.ID = "https://github.com/jrschumacher/adder/schema/config.json"
+
+	return json.MarshalIndent(schema, "", "  ")
+}
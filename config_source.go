@@ -0,0 +1,173 @@
+package adder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SourceDefault is the source of config values nobody set
+const SourceDefault = "default"
+
+// EnvPrefix starts the names of environment variables overriding config values, e.g. ADDER_INPUT
+const EnvPrefix = "ADDER_"
+
+// ConfigKeys are the config values that can be set from a config file, the environment or flags
+// Nested values use dots, e.g. validation.strict
+var ConfigKeys = []string{
+	"binary_name",
+	"input",
+	"output",
+	"package",
+	"generated_file_suffix",
+	"index_format",
+	"package_strategy",
+	"templates",
+	"validation.strict",
+}
+
+// field returns the string or bool field of a config key
+func (c *Config) field(key string) (*string, *bool, error) {
+	switch key {
+	case "binary_name":
+		return &c.BinaryName, nil, nil
+	case "input":
+		return &c.InputDir, nil, nil
+	case "output":
+		return &c.OutputDir, nil, nil
+	case "package":
+		return &c.Package, nil, nil
+	case "generated_file_suffix":
+		return &c.GeneratedFileSuffix, nil, nil
+	case "index_format":
+		return &c.IndexFormat, nil, nil
+	case "package_strategy":
+		return &c.PackageStrategy, nil, nil
+	case "templates":
+		return &c.TemplatesDir, nil, nil
+	case "validation.strict":
+		return nil, &c.Validation.Strict, nil
+	}
+	return nil, nil, fmt.Errorf("unknown config key %q (valid: %s)", key, strings.Join(ConfigKeys, ", "))
+}
+
+// Get returns a config value by key
+func (c *Config) Get(key string) (string, error) {
+	s, b, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	if b != nil {
+		return strconv.FormatBool(*b), nil
+	}
+	return *s, nil
+}
+
+// Set changes a config value by key and records where the value came from, e.g. "--input"
+// Values are checked as in a config file, e.g. index_format must be a known format
+func (c *Config) Set(key, value, source string) error {
+	s, b, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	if err := checkConfigValue(key, value); err != nil {
+		return err
+	}
+	if b != nil {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a boolean", key, value)
+		}
+		*b = parsed
+	} else {
+		*s = value
+	}
+	c.setSource(key, source)
+	return nil
+}
+
+// Source returns where a config value came from: a config file, an environment variable, a flag or SourceDefault
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// Warnings returns a message for each deprecated key of the config file the config was loaded from
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+func (c *Config) setSource(key, source string) {
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[key] = source
+}
+
+// EnvName returns the environment variable overriding a config key, e.g. ADDER_VALIDATION_STRICT
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyEnv overrides config values with the ADDER_* environment variables that are set
+// lookup is usually os.LookupEnv; targets are overridden as well
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, config := range append([]*Config{c}, c.Targets...) {
+		for _, key := range ConfigKeys {
+			name := EnvName(key)
+			value, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if err := config.Set(key, value, name); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the config, including its targets and the sources of its values
+func (c *Config) Clone() *Config {
+	clone := *c
+	if c.sources != nil {
+		clone.sources = make(map[string]string, len(c.sources))
+		for key, source := range c.sources {
+			clone.sources[key] = source
+		}
+	}
+	if c.Targets != nil {
+		clone.Targets = make([]*Config, len(c.Targets))
+		for i, target := range c.Targets {
+			clone.Targets[i] = target.Clone()
+		}
+	}
+	return &clone
+}
+
+// setFileSources records source for every config key set in a YAML mapping of a config file
+func (c *Config) setFileSources(item yaml.MapSlice, source string) {
+	for _, key := range mappingKeys(item, "") {
+		if _, _, err := c.field(key); err == nil {
+			c.setSource(key, source)
+		}
+	}
+}
+
+// mappingKeys returns the keys of a YAML mapping, joining nested keys with dots
+func mappingKeys(item yaml.MapSlice, prefix string) []string {
+	var keys []string
+	for _, entry := range item {
+		key := prefix + fmt.Sprint(entry.Key)
+		if nested, ok := entry.Value.(yaml.MapSlice); ok {
+			keys = append(keys, mappingKeys(nested, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
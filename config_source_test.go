@@ -0,0 +1,114 @@
+package adder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_SetGetSource(t *testing.T) {
+	config := DefaultConfig()
+	if got := config.Source("input"); got != SourceDefault {
+		t.Errorf("Source(input) = %q, want %q", got, SourceDefault)
+	}
+
+	if err := config.Set("input", "docs", "--input"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := config.Set("validation.strict", "true", "ADDER_VALIDATION_STRICT"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if config.InputDir != "docs" || !config.Validation.Strict {
+		t.Errorf("Set() did not change the config: %+v", config)
+	}
+	if got, _ := config.Get("validation.strict"); got != "true" {
+		t.Errorf("Get(validation.strict) = %q, want true", got)
+	}
+	if got := config.Source("input"); got != "--input" {
+		t.Errorf("Source(input) = %q, want --input", got)
+	}
+
+	if err := config.Set("validation.strict", "maybe", "test"); err == nil {
+		t.Error("Set() expected an error for an invalid boolean")
+	}
+	if err := config.Set("index_format", "bogus", "ADDER_INDEX_FORMAT"); err == nil || config.IndexFormat == "bogus" {
+		t.Errorf("Set() error = %v, want an error for an unknown index format", err)
+	}
+	if err := config.Set("package_strategy", "path", "--package-strategy"); err != nil || config.PackageStrategy != "path" {
+		t.Errorf("Set() error = %v, want package_strategy path", err)
+	}
+	if err := config.Set("inputs", "docs", "test"); err == nil || !strings.Contains(err.Error(), "unknown config key") {
+		t.Errorf("Set() error = %v, want an unknown key", err)
+	}
+
+	// Clones do not share sources
+	clone := config.Clone()
+	_ = clone.Set("input", "other", "--input")
+	_ = clone.Set("output", "out", "ADDER_OUTPUT")
+	if config.InputDir != "docs" || config.Source("output") != SourceDefault {
+		t.Error("changing a clone changed the original")
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"ADDER_OUTPUT":            "internal/generated",
+		"ADDER_VALIDATION_STRICT": "1",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	config := DefaultConfig()
+	config.Targets = []*Config{{Name: "a"}}
+	if err := config.ApplyEnv(lookup); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+	for _, c := range []*Config{config, config.Targets[0]} {
+		if c.OutputDir != "internal/generated" || !c.Validation.Strict {
+			t.Errorf("ApplyEnv() did not override %+v", c)
+		}
+		if got := c.Source("output"); got != "ADDER_OUTPUT" {
+			t.Errorf("Source(output) = %q, want ADDER_OUTPUT", got)
+		}
+	}
+	if got := config.Source("input"); got != SourceDefault {
+		t.Errorf("Source(input) = %q, want %q", got, SourceDefault)
+	}
+
+	env["ADDER_VALIDATION_STRICT"] = "sometimes"
+	if err := DefaultConfig().ApplyEnv(lookup); err == nil || !strings.Contains(err.Error(), "ADDER_VALIDATION_STRICT") {
+		t.Errorf("ApplyEnv() error = %v, want the variable named", err)
+	}
+}
+
+func TestLoadConfigFile_Sources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".adder.yaml")
+	writeTestFile(t, path, targetsTestConfig)
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+
+	server, agent := config.Targets[0], config.Targets[1]
+	tests := []struct {
+		config *Config
+		key    string
+		want   string
+	}{
+		{config, "package_strategy", path},
+		{config, "validation.strict", path},
+		{config, "binary_name", SourceDefault},
+		{server, "package_strategy", path},
+		{server, "binary_name", path + " (target server)"},
+		{agent, "validation.strict", path + " (target agent)"},
+		{agent, "generated_file_suffix", SourceDefault},
+	}
+	for _, tt := range tests {
+		if got := tt.config.Source(tt.key); got != tt.want {
+			t.Errorf("%s: Source(%s) = %q, want %q", tt.config.Name, tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package adder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindConfigFile(t *testing.T) {
+	module := t.TempDir()
+	writeTestFile(t, filepath.Join(module, "go.mod"), "module example.com/app\n")
+	writeTestFile(t, filepath.Join(module, ".adder.yaml"), "binary_name: app\n")
+	pkg := filepath.Join(module, "internal", "cli")
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindConfigFile(pkg)
+	if err != nil {
+		t.Fatalf("FindConfigFile() error = %v", err)
+	}
+	if want := filepath.Join(module, ".adder.yaml"); got != want {
+		t.Errorf("FindConfigFile() = %q, want %q", got, want)
+	}
+
+	// A nested module does not use the config file of the outer one
+	nested := filepath.Join(module, "tools")
+	writeTestFile(t, filepath.Join(nested, "go.mod"), "module example.com/tools\n")
+	if got, err := FindConfigFile(nested); err != nil || got != "" {
+		t.Errorf("FindConfigFile() in a nested module = %q, %v; want none", got, err)
+	}
+}
+
+func TestLoadConfig_ResolvesPaths(t *testing.T) {
+	module := t.TempDir()
+	writeTestFile(t, filepath.Join(module, "go.mod"), "module example.com/app\n")
+	writeTestFile(t, filepath.Join(module, ".adder.yaml"), "binary_name: app\noutput: internal/generated\ntemplates: /etc/adder\n")
+	pkg := filepath.Join(module, "cmd", "app")
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(pkg)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if want := filepath.Join(module, "docs", "commands"); config.InputDir != want {
+		t.Errorf("InputDir = %q, want the default resolved against the config file: %q", config.InputDir, want)
+	}
+	if want := filepath.Join(module, "internal", "generated"); config.OutputDir != want {
+		t.Errorf("OutputDir = %q, want %q", config.OutputDir, want)
+	}
+	if config.TemplatesDir != "/etc/adder" {
+		t.Errorf("TemplatesDir = %q, absolute paths should be kept", config.TemplatesDir)
+	}
+
+	// Without a config file the defaults stay relative to the working directory
+	empty := t.TempDir()
+	writeTestFile(t, filepath.Join(empty, "go.mod"), "module example.com/empty\n")
+	config, err = LoadConfig(empty)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.InputDir != "docs/commands" {
+		t.Errorf("InputDir = %q, want the default", config.InputDir)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "adder.yaml")
+	writeTestFile(t, path, "binary_name: app\ninput: commands\n")
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if config.BinaryName != "app" || config.InputDir != filepath.Join(dir, "commands") {
+		t.Errorf("LoadConfigFile() = %+v", config)
+	}
+
+	if _, err := LoadConfigFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadConfigFile() expected an error for a missing file")
+	}
+	writeTestFile(t, path, "input: commands\n")
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("LoadConfigFile() expected an error without binary_name")
+	}
+}
@@ -0,0 +1,71 @@
+package adder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// YesFlag is the flag destructive commands use to skip confirmation
+const YesFlag = "yes"
+
+// yesUsage is the help text of --yes
+const yesUsage = "Skip the confirmation prompt"
+
+// ErrAborted is returned when a confirmation is declined
+var ErrAborted = errors.New("aborted")
+
+// AddYesFlag registers the --yes flag on a destructive generated command
+func AddYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP(YesFlag, "y", false, yesUsage)
+}
+
+// Confirm asks for confirmation before a destructive command runs
+// message is a text/template executed with the request (e.g. "Delete {{.Arguments.Name}}?");
+// an empty message asks whether to run the command. prompter is the one the command prompted
+// with, from CommandOptions.PrompterFor. Without --yes, confirmation requires a prompter,
+// so non-interactive runs (a nil prompter) are refused with a *UsageError
+func Confirm(cmd *cobra.Command, prompter *Prompter, message string, req Request) error {
+	if yes, _ := cmd.Flags().GetBool(YesFlag); yes {
+		return nil
+	}
+
+	if prompter == nil {
+		return NewUsageError("%s requires confirmation: use --%s to run it non-interactively", cmd.CommandPath(), YesFlag)
+	}
+
+	question, err := renderConfirmation(cmd, message, req)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := prompter.Confirm(question, false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrAborted
+	}
+	return nil
+}
+
+// renderConfirmation executes the confirmation message template with the request
+func renderConfirmation(cmd *cobra.Command, message string, req Request) (string, error) {
+	if message == "" {
+		return fmt.Sprintf("Run %s?", cmd.CommandPath()), nil
+	}
+
+	tmpl, err := template.New("confirm").Option("missingkey=error").Parse(message)
+	if err != nil {
+		return "", fmt.Errorf("parsing confirmation message: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, req); err != nil {
+		return "", fmt.Errorf("rendering confirmation message: %w", err)
+	}
+	return b.String(), nil
+}
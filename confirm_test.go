@@ -0,0 +1,75 @@
+package adder
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// confirmRequest is a minimal request used to render confirmation messages
+type confirmRequest struct {
+	Arguments struct{ Name string }
+}
+
+func (r *confirmRequest) GetRawArguments() []string { return nil }
+
+func TestConfirm(t *testing.T) {
+	req := &confirmRequest{}
+	req.Arguments.Name = "prod"
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    *string // nil means no prompter
+		message  string
+		wantErr  error
+		wantCode int
+		wantOut  string
+	}{
+		{name: "yes flag skips prompt", args: []string{"--yes"}},
+		{name: "short yes flag", args: []string{"-y"}},
+		{name: "non-interactive without yes", wantCode: ExitCodeUsage},
+		{name: "confirmed", input: strPtr("y\n"), message: "Delete {{.Arguments.Name}}?", wantOut: "Delete prod? [y/N]: "},
+		{name: "declined by default", input: strPtr("\n"), wantErr: ErrAborted, wantOut: "Run app? [y/N]: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "app"}
+			cmd.SetIn(strings.NewReader(""))
+			AddYesFlag(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatalf("parsing flags: %v", err)
+			}
+
+			var out bytes.Buffer
+			var prompter *Prompter
+			if tt.input != nil {
+				prompter = NewPrompter(strings.NewReader(*tt.input), &out)
+			}
+
+			err := Confirm(cmd, prompter, tt.message, req)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantCode != 0:
+				if ExitCode(err) != tt.wantCode {
+					t.Errorf("exit code = %d (%v), want %d", ExitCode(err), err, tt.wantCode)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.wantOut {
+				t.Errorf("prompt = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,77 @@
+package adder
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFileChange_Diff(t *testing.T) {
+	numbered := func(from, to int, replace map[int]string) string {
+		var b strings.Builder
+		for i := from; i <= to; i++ {
+			if line, ok := replace[i]; ok {
+				if line != "" {
+					b.WriteString(line + "\n")
+				}
+				continue
+			}
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name   string
+		change FileChange
+		want   string
+	}{
+		{
+			name:   "unchanged",
+			change: FileChange{Path: "a.go", Old: []byte("x\n"), New: []byte("x\n")},
+			want:   "",
+		},
+		{
+			name:   "create",
+			change: FileChange{Path: "a.go", Action: ChangeCreate, New: []byte("one\ntwo\n")},
+			want:   "--- /dev/null\n+++ b/a.go\n@@ -0,0 +1,2 @@\n+one\n+two\n",
+		},
+		{
+			name: "single change with context",
+			change: FileChange{
+				Path: "a.go",
+				Old:  []byte(numbered(1, 10, nil)),
+				New:  []byte(numbered(1, 10, map[int]string{5: "changed"})),
+			},
+			want: "--- a/a.go\n+++ b/a.go\n@@ -2,7 +2,7 @@\n line 2\n line 3\n line 4\n-line 5\n+changed\n line 6\n line 7\n line 8\n",
+		},
+		{
+			name: "separate hunks",
+			change: FileChange{
+				Path: "a.go",
+				Old:  []byte(numbered(1, 20, nil)),
+				New:  []byte(numbered(1, 20, map[int]string{2: "", 18: "new 18"})),
+			},
+			want: "--- a/a.go\n+++ b/a.go\n" +
+				"@@ -1,5 +1,4 @@\n line 1\n-line 2\n line 3\n line 4\n line 5\n" +
+				"@@ -15,6 +14,6 @@\n line 15\n line 16\n line 17\n-line 18\n+new 18\n line 19\n line 20\n",
+		},
+		{
+			name: "nearby changes share a hunk",
+			change: FileChange{
+				Path: "a.go",
+				Old:  []byte(numbered(1, 12, nil)),
+				New:  []byte(numbered(1, 12, map[int]string{3: "x", 8: "y"})),
+			},
+			want: "--- a/a.go\n+++ b/a.go\n@@ -1,11 +1,11 @@\n line 1\n line 2\n-line 3\n+x\n line 4\n line 5\n line 6\n line 7\n-line 8\n+y\n line 9\n line 10\n line 11\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Diff(); got != tt.want {
+				t.Errorf("Diff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package adder
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Reference documentation formats
+const (
+	DocsFormatMarkdown   = "markdown"   // Plain markdown with a heading per page
+	DocsFormatHugo       = "hugo"       // Hugo frontmatter, _index.md section pages and relref links
+	DocsFormatDocusaurus = "docusaurus" // Docusaurus frontmatter and index.md category pages
+)
+
+// DocsFormats lists the supported reference documentation formats
+var DocsFormats = []string{DocsFormatMarkdown, DocsFormatHugo, DocsFormatDocusaurus}
+
+// ReferenceDocs renders a reference page for every command of the tree, keyed by slash separated path
+// Commands with subcommands become index pages of a directory, so the site mirrors the command tree
+func ReferenceDocs(root *CommandNode, format string) (map[string][]byte, error) {
+	if !isDocsFormat(format) {
+		return nil, fmt.Errorf("unknown docs format %q (must be %s)", format, joinEnumValues(DocsFormats, "or"))
+	}
+
+	pages := make(map[string][]byte)
+	err := root.Walk(func(node *CommandNode) error {
+		page := docsPagePath(node, format)
+		if _, exists := pages[page]; exists {
+			return fmt.Errorf("%s: more than one command would be written to %s", node.CommandPath(), page)
+		}
+		pages[page] = renderReferencePage(node, format)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// WriteReferenceDocs writes the reference pages of the command tree below dir and returns the paths written
+func WriteReferenceDocs(root *CommandNode, dir, format string) ([]string, error) {
+	pages, err := ReferenceDocs(root, format)
+	if err != nil {
+		return nil, err
+	}
+	return writeFileMap(dir, pages)
+}
+
+// isDocsFormat reports whether format is one of DocsFormats
+func isDocsFormat(format string) bool {
+	for _, f := range DocsFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFileMap writes files keyed by slash separated path below dir and returns the sorted paths written
+func writeFileMap(dir string, files map[string][]byte) ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, fmt.Errorf("creating directory for %s: %w", p, err)
+		}
+		if err := writeFileAtomic(p, content, 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", p, err)
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// docsPagePath returns the page of a command: an index page for commands with subcommands
+func docsPagePath(node *CommandNode, format string) string {
+	segments := node.Path[1:]
+	if node.Parent == nil || len(node.Children) > 0 {
+		index := "index.md"
+		if format == DocsFormatHugo {
+			index = "_index.md"
+		}
+		return path.Join(append(append([]string{}, segments...), index)...)
+	}
+	return path.Join(path.Join(segments[:len(segments)-1]...), node.Name()+".md")
+}
+
+// docsLink returns a link from the page of one command to the page of another
+func docsLink(from, to *CommandNode, format string) string {
+	fromDir := path.Dir(docsPagePath(from, format))
+	target, err := filepath.Rel(filepath.FromSlash(fromDir), filepath.FromSlash(docsPagePath(to, format)))
+	if err != nil {
+		target = docsPagePath(to, format)
+	}
+	target = filepath.ToSlash(target)
+
+	if format == DocsFormatHugo {
+		return fmt.Sprintf(`{{< relref "%s" >}}`, target)
+	}
+	return target
+}
+
+// renderReferencePage renders the reference page of a single command
+func renderReferencePage(node *CommandNode, format string) []byte {
+	cmd := node.Command
+	var b strings.Builder
+
+	switch format {
+	case DocsFormatHugo:
+		writeDocsFrontmatter(&b, yaml.MapSlice{
+			{Key: "title", Value: node.CommandPath()},
+			{Key: "linkTitle", Value: node.Name()},
+			{Key: "description", Value: cmd.Title},
+		})
+	case DocsFormatDocusaurus:
+		writeDocsFrontmatter(&b, yaml.MapSlice{
+			{Key: "title", Value: node.CommandPath()},
+			{Key: "sidebar_label", Value: node.Name()},
+			{Key: "description", Value: cmd.Title},
+		})
+	default:
+		fmt.Fprintf(&b, "# %s\n\n", node.CommandPath())
+	}
+
+	if cmd.Title != "" {
+		fmt.Fprintf(&b, "%s\n\n", cmd.Title)
+	}
+
+	body := docsBody(cmd.Description)
+	if !hasMarkdownHeading(body, "Usage") {
+		fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n\n", node.Usage())
+	}
+
+	if len(cmd.Aliases) > 0 {
+		aliases := make([]string, len(cmd.Aliases))
+		for i, alias := range cmd.Aliases {
+			aliases[i] = "`" + alias + "`"
+		}
+		fmt.Fprintf(&b, "## Aliases\n\n%s\n\n", strings.Join(aliases, ", "))
+	}
+
+	if body != "" {
+		fmt.Fprintf(&b, "%s\n\n", body)
+	}
+
+	if len(cmd.Arguments) > 0 {
+		b.WriteString("## Arguments\n\n| Argument | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+		for _, arg := range cmd.Arguments {
+			required := "no"
+			if arg.Required {
+				required = "yes"
+			}
+			argType := arg.Type
+			if argType == "" {
+				argType = TypeString
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", arg.Name, argType, required, docsCell(arg.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	writeDocsFlagTable(&b, "Flags", append(append(append([]Flag{}, cmd.Flags...), cmd.PersistentFlags...), generatedFlags(cmd)...))
+	writeDocsFlagTable(&b, "Global Flags", node.InheritedFlags())
+
+	if len(cmd.Errors) > 0 {
+		b.WriteString("## Exit Codes\n\n| Code | Error | Description |\n| --- | --- | --- |\n")
+		for _, e := range cmd.Errors {
+			fmt.Fprintf(&b, "| %d | `%s` | %s |\n", e.Code, e.Name, docsCell(e.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(node.Children) > 0 {
+		b.WriteString("## Subcommands\n\n| Command | Description |\n| --- | --- |\n")
+		for _, child := range node.Children {
+			fmt.Fprintf(&b, "| [%s](%s) | %s |\n", child.CommandPath(), docsLink(node, child, format), docsCell(child.Command.Title))
+		}
+		b.WriteString("\n")
+	}
+
+	if node.Parent != nil {
+		fmt.Fprintf(&b, "## See Also\n\n- [%s](%s)", node.Parent.CommandPath(), docsLink(node, node.Parent, format))
+		if node.Parent.Command.Title != "" {
+			fmt.Fprintf(&b, " - %s", node.Parent.Command.Title)
+		}
+		b.WriteString("\n")
+	}
+
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n")
+}
+
+// generatedFlags returns the flags generated commands register besides the declared ones,
+// in the order the command template adds them
+func generatedFlags(cmd *Command) []Flag {
+	flags := []Flag{{Name: FromFileFlag, Type: TypeString, Description: fromFileUsage}}
+	if cmd.Destructive {
+		flags = append(flags, Flag{Name: YesFlag, Shorthand: "y", Type: TypeBool, Default: false, Description: yesUsage})
+	}
+	if cmd.Output != nil {
+		flags = append(flags, Flag{Name: OutputFlag, Shorthand: "o", Type: TypeString, Default: cmd.Output.GetDefaultFormat(), Description: outputUsage})
+	}
+	return flags
+}
+
+// writeDocsFrontmatter writes YAML frontmatter, leaving out empty values
+func writeDocsFrontmatter(b *strings.Builder, fields yaml.MapSlice) {
+	var kept yaml.MapSlice
+	for _, field := range fields {
+		if field.Value != "" {
+			kept = append(kept, field)
+		}
+	}
+	data, err := yaml.Marshal(kept)
+	if err != nil {
+		data = nil
+	}
+	fmt.Fprintf(b, "---\n%s---\n\n", data)
+}
+
+// writeDocsFlagTable writes a table of flags with their shorthand, type, default and enum values
+func writeDocsFlagTable(b *strings.Builder, heading string, flags []Flag) {
+	if len(flags) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "## %s\n\n| Flag | Type | Default | Description |\n| --- | --- | --- | --- |\n", heading)
+	for _, f := range flags {
+		name := "`--" + f.Name + "`"
+		if f.Shorthand != "" {
+			name = "`-" + f.Shorthand + "`, " + name
+		}
+
+		defaultValue := formatFlagDefault(f)
+		if defaultValue != "" {
+			defaultValue = "`" + defaultValue + "`"
+		}
+
+		description := docsCell(f.Description)
+		if f.Required {
+			description = strings.TrimSpace(description + " (required)")
+		}
+		if len(f.Enum) > 0 {
+			values := make([]string, len(f.Enum))
+			for i, v := range f.Enum {
+				values[i] = "`" + v + "`"
+			}
+			if description != "" && !strings.HasSuffix(description, ".") {
+				description += "."
+			}
+			description = strings.TrimSpace(description + " One of: " + strings.Join(values, ", ") + ".")
+		}
+
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", name, f.Type, defaultValue, description)
+	}
+	b.WriteString("\n")
+}
+
+// docsBody returns the markdown body of a command without its leading heading
+// The page heading or frontmatter title already names the command
+func docsBody(description string) string {
+	body := strings.TrimSpace(description)
+	if strings.HasPrefix(body, "# ") {
+		if i := strings.Index(body, "\n"); i >= 0 {
+			body = strings.TrimSpace(body[i+1:])
+		} else {
+			body = ""
+		}
+	}
+	return body
+}
+
+// hasMarkdownHeading reports whether the markdown has a level two heading with the given text
+func hasMarkdownHeading(markdown, heading string) bool {
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.TrimSpace(line) == "## "+heading {
+			return true
+		}
+	}
+	return false
+}
+
+// docsCell escapes text for a markdown table cell
+func docsCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
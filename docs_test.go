@@ -0,0 +1,134 @@
+package adder
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func docsTestTree() *CommandNode {
+	return BuildCommandTree("app", []*Command{
+		{Name: "app", Title: "An app", FilePath: "app.md", IsRootCommand: true, PersistentFlags: []Flag{{Name: "verbose", Shorthand: "v", Type: TypeBool, Default: false, Description: "Verbose output"}}},
+		{Name: "user", Title: "Manage users", FilePath: "user.md"},
+		{
+			Name:        "create [name]",
+			Title:       "Create a user",
+			FilePath:    "user/create.md",
+			Aliases:     []string{"add"},
+			Description: "# Create\n\nCreates a user.",
+			Arguments:   []Argument{{Name: "name", Description: "User name", Required: true, Type: TypeString}},
+			Flags: []Flag{
+				{Name: "role", Type: TypeString, Default: "viewer", Description: "Role | level", Enum: []string{"viewer", "admin"}},
+				{Name: "email", Type: TypeString, Required: true, Description: "Email address"},
+			},
+			Errors:      []CommandError{{Name: "exists", Code: 4, Description: "User already exists"}},
+			Destructive: true,
+			Output:      &Output{Format: OutputFormatJSON, Fields: []OutputField{{Name: "id", Type: TypeInt}}},
+		},
+	})
+}
+
+func TestReferenceDocs(t *testing.T) {
+	tests := []struct {
+		format    string
+		wantPages []string
+		want      map[string][]string
+	}{
+		{
+			format:    DocsFormatMarkdown,
+			wantPages: []string{"index.md", "user/create.md", "user/index.md"},
+			want: map[string][]string{
+				"user/create.md": {
+					"# app user create\n",
+					"app user create <name> [flags]",
+					"## Aliases\n\n`add`",
+					"Creates a user.",
+					"| `name` | string | yes | User name |",
+					"| `--role` | string | `viewer` | Role \\| level. One of: `viewer`, `admin`. |",
+					"| `--email` | string |  | Email address (required) |",
+					"| `--from-file` | string |  | Populate the request from a JSON or YAML file (use - for stdin) |",
+					"| `-y`, `--yes` | bool | `false` | Skip the confirmation prompt |",
+					"| `-o`, `--output` | string | `json` | Output format (json, yaml, table, or ndjson) |",
+					"## Global Flags",
+					"| `-v`, `--verbose` | bool | `false` | Verbose output |",
+					"| 4 | `exists` | User already exists |",
+					"- [app user](index.md) - Manage users",
+				},
+				"user/index.md": {
+					"| [app user create](create.md) | Create a user |",
+					"- [app](../index.md) - An app",
+				},
+			},
+		},
+		{
+			format:    DocsFormatHugo,
+			wantPages: []string{"_index.md", "user/_index.md", "user/create.md"},
+			want: map[string][]string{
+				"user/create.md": {
+					"---\ntitle: app user create\nlinkTitle: create\ndescription: Create a user\n---\n",
+					`- [app user]({{< relref "_index.md" >}})`,
+				},
+			},
+		},
+		{
+			format:    DocsFormatDocusaurus,
+			wantPages: []string{"index.md", "user/create.md", "user/index.md"},
+			want: map[string][]string{
+				"user/create.md": {"---\ntitle: app user create\nsidebar_label: create\ndescription: Create a user\n---\n"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			pages, err := ReferenceDocs(docsTestTree(), tt.format)
+			if err != nil {
+				t.Fatalf("ReferenceDocs() error = %v", err)
+			}
+
+			var names []string
+			for name := range pages {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != strings.Join(tt.wantPages, ",") {
+				t.Fatalf("pages = %v, want %v", names, tt.wantPages)
+			}
+
+			for page, wants := range tt.want {
+				content := string(pages[page])
+				for _, want := range wants {
+					if !strings.Contains(content, want) {
+						t.Errorf("%s missing %q:\n%s", page, want, content)
+					}
+				}
+			}
+			if strings.Contains(string(pages["user/create.md"]), "# Create\n") {
+				t.Errorf("body heading should be dropped:\n%s", pages["user/create.md"])
+			}
+		})
+	}
+}
+
+func TestReferenceDocs_UnknownFormat(t *testing.T) {
+	if _, err := ReferenceDocs(docsTestTree(), "mkdocs"); err == nil {
+		t.Error("ReferenceDocs() expected an error for an unknown format")
+	}
+}
+
+func TestWriteReferenceDocs(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := WriteReferenceDocs(docsTestTree(), dir, DocsFormatMarkdown)
+	if err != nil {
+		t.Fatalf("WriteReferenceDocs() error = %v", err)
+	}
+	if len(paths) != 3 {
+		t.Errorf("wrote %d pages, want 3", len(paths))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "user", "create.md")); err != nil {
+		t.Errorf("user/create.md not written: %v", err)
+	}
+}
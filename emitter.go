@@ -0,0 +1,165 @@
+package adder
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Emitter writes additional artifacts from the parsed command tree, e.g. a permissions manifest,
+// a telemetry schema or a completion spec
+// Emitters run after the Go code is generated, in the same pass, and see every command
+type Emitter interface {
+	// Name identifies the emitter in errors and the lockfile
+	Name() string
+	// Emit returns the files to write; root is the root command of the tree
+	Emit(ctx context.Context, root *CommandNode) ([]EmittedFile, error)
+}
+
+// EmittedFile is a file produced by an Emitter
+type EmittedFile struct {
+	Path    string // Slash separated path relative to the output directory
+	Content []byte
+}
+
+// emitterFunc adapts a function to the Emitter interface
+type emitterFunc struct {
+	name string
+	emit func(ctx context.Context, root *CommandNode) ([]EmittedFile, error)
+}
+
+func (e emitterFunc) Name() string { return e.name }
+
+func (e emitterFunc) Emit(ctx context.Context, root *CommandNode) ([]EmittedFile, error) {
+	return e.emit(ctx, root)
+}
+
+// NewEmitter returns an Emitter with the given name that calls emit
+func NewEmitter(name string, emit func(ctx context.Context, root *CommandNode) ([]EmittedFile, error)) Emitter {
+	return emitterFunc{name: name, emit: emit}
+}
+
+// emittedOutput is an emitted file resolved to its path on disk
+type emittedOutput struct {
+	emitter string
+	output  string // Slash separated path relative to the output directory, as recorded in the lockfile
+	path    string
+	content []byte
+}
+
+// emit runs the registered emitters on the tree of the parsed commands
+// Paths must stay inside the output directory and not collide with generated Go files or each other
+// Requires the commands to be parsed
+func (g *Generator) emit(ctx context.Context) ([]emittedOutput, error) {
+	if len(g.emitters) == 0 {
+		return nil, nil
+	}
+
+	// Paths already taken by the generated Go files and the lockfile
+	taken := map[string]string{g.lockPath(): "the lockfile"}
+	for filename := range g.groupCommandsByFile() {
+		taken[filename] = "generated code"
+	}
+
+	root := BuildCommandTree(g.config.BinaryName, g.commands)
+
+	var outputs []emittedOutput
+	for _, emitter := range g.emitters {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		files, err := emitter.Emit(ctx, root)
+		if err != nil {
+			return nil, fmt.Errorf("emitter %s: %w", emitter.Name(), err)
+		}
+
+		for _, file := range files {
+			output := path.Clean(file.Path)
+			if file.Path == "" || path.IsAbs(output) || output == ".." || strings.HasPrefix(output, "../") {
+				return nil, fmt.Errorf("emitter %s: path %q must be relative to the output directory", emitter.Name(), file.Path)
+			}
+
+			filename := filepath.Join(g.config.OutputDir, filepath.FromSlash(output))
+			if owner, ok := taken[filename]; ok {
+				return nil, fmt.Errorf("emitter %s: %s is already written by %s", emitter.Name(), output, owner)
+			}
+			taken[filename] = "emitter " + emitter.Name()
+
+			outputs = append(outputs, emittedOutput{
+				emitter: emitter.Name(),
+				output:  output,
+				path:    filename,
+				content: file.Content,
+			})
+		}
+	}
+
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].output < outputs[j].output })
+	return outputs, nil
+}
+
+// writeEmitted writes emitted files whose content changed and returns their lockfile entries
+// It reports how many files were left untouched
+func (g *Generator) writeEmitted(outputs []emittedOutput) ([]lockEntry, int, error) {
+	entries := make([]lockEntry, len(outputs))
+	skipped := 0
+	for i, out := range outputs {
+		entries[i] = lockEntry{
+			Output:       out.output,
+			Hash:         hashContent(out.content),
+			Emitter:      out.emitter,
+			AdderVersion: generatorVersion(),
+		}
+
+		// Identical content is not rewritten, so modification times only change with the artifact
+		if current, err := g.readOutput(out.path); err == nil && !g.force && string(current) == string(out.content) {
+			skipped++
+			continue
+		}
+		if err := g.writeFile(out.path, string(out.content)); err != nil {
+			return nil, 0, fmt.Errorf("emitter %s: %w", out.emitter, err)
+		}
+	}
+	return entries, skipped, nil
+}
+
+// staleEmitted returns files recorded as emitted in the lockfile that no emitter writes anymore
+// Without outputs, every recorded emitted file is returned. Files modified since they were
+// emitted, e.g. by a user taking them over, are returned as kept with their lockfile entries instead.
+func (g *Generator) staleEmitted(previous map[string]lockEntry, outputs []emittedOutput) (stale, kept []string, keptEntries []lockEntry) {
+	current := make(map[string]bool, len(outputs))
+	for _, out := range outputs {
+		current[out.output] = true
+	}
+
+	for output, entry := range previous {
+		if entry.Emitter == "" || current[output] || output == ".." || strings.HasPrefix(output, "../") || path.IsAbs(output) {
+			continue
+		}
+		filename := filepath.Join(g.config.OutputDir, filepath.FromSlash(output))
+		content, err := fs.ReadFile(g.output, output)
+		if err != nil {
+			continue
+		}
+		if hashContent(content) != entry.Hash {
+			kept = append(kept, filename)
+			keptEntries = append(keptEntries, entry)
+			continue
+		}
+		stale = append(stale, filename)
+	}
+	sort.Strings(stale)
+	sort.Strings(kept)
+	sort.Slice(keptEntries, func(i, j int) bool { return keptEntries[i].Output < keptEntries[j].Output })
+	return stale, kept, keptEntries
+}
+
+// AddEmitter registers emitters that run after every generation
+func (g *Generator) AddEmitter(emitters ...Emitter) {
+	g.emitters = append(g.emitters, emitters...)
+}
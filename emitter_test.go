@@ -0,0 +1,213 @@
+package adder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// manifestEmitter writes the path of every command to manifest/commands.txt
+func manifestEmitter(enabled *bool) Emitter {
+	return NewEmitter("manifest", func(_ context.Context, root *CommandNode) ([]EmittedFile, error) {
+		if !*enabled {
+			return nil, nil
+		}
+		var paths []string
+		err := root.Walk(func(node *CommandNode) error {
+			paths = append(paths, node.CommandPath())
+			return nil
+		})
+		return []EmittedFile{{Path: "manifest/commands.txt", Content: []byte(strings.Join(paths, "\n") + "\n")}}, err
+	})
+}
+
+func TestAdder_WithEmitter(t *testing.T) {
+	config := cleanTestConfig(t, "app", "app/serve")
+	config.BinaryName = "app"
+	enabled := true
+	a := New(config, WithEmitter(manifestEmitter(&enabled)))
+
+	manifest := filepath.Join(config.OutputDir, "manifest", "commands.txt")
+	if err := a.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatalf("Expected the emitted file to be written: %v", err)
+	}
+	if string(content) != "app\napp serve\n" {
+		t.Errorf("emitted content = %q", content)
+	}
+	if got := a.GetStats()["emitted_files"]; got != 1 {
+		t.Errorf("emitted_files = %d, want 1", got)
+	}
+	lock, _ := os.ReadFile(filepath.Join(config.OutputDir, LockFileName))
+	if !strings.Contains(string(lock), "emitter: manifest") {
+		t.Errorf("lockfile should record the emitted file:\n%s", lock)
+	}
+
+	// Emitted files take part in --check
+	if err := os.WriteFile(manifest, []byte("stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := a.Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != manifest || changes[0].Action != ChangeUpdate {
+		t.Errorf("Changes() = %+v, want an update of %s", changes, manifest)
+	}
+
+	// Files the emitter no longer writes are removed like stale generated code
+	if err := a.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	enabled = false
+	if err := a.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(manifest); !os.IsNotExist(err) {
+		t.Errorf("stale emitted file should be removed, stat error = %v", err)
+	}
+	if removed := a.GetRemovedFiles(); len(removed) != 1 || removed[0] != manifest {
+		t.Errorf("GetRemovedFiles() = %v, want [%s]", removed, manifest)
+	}
+}
+
+func TestAdder_CleanEmitted(t *testing.T) {
+	config := cleanTestConfig(t, "app")
+	config.BinaryName = "app"
+	enabled := true
+	a := New(config, WithEmitter(manifestEmitter(&enabled)))
+	if err := a.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	removed, err := a.CleanWithOptions(context.Background(), CleanOptions{All: true})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("removed %v, want the generated file, the emitted file and the lockfile", removed)
+	}
+	if _, err := os.Stat(filepath.Join(config.OutputDir, "manifest")); !os.IsNotExist(err) {
+		t.Error("emptied emitter directory should be removed")
+	}
+}
+
+func TestAdder_KeepModifiedEmitted(t *testing.T) {
+	config := cleanTestConfig(t, "app")
+	config.BinaryName = "app"
+	enabled := true
+	a := New(config, WithEmitter(manifestEmitter(&enabled)))
+	if err := a.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// A user took over the emitted file, so it is no longer removed as stale
+	manifest := filepath.Join(config.OutputDir, "manifest", "commands.txt")
+	if err := os.WriteFile(manifest, []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	enabled = false
+	for i := 0; i < 2; i++ {
+		if err := a.GenerateWithContext(context.Background()); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if content, err := os.ReadFile(manifest); err != nil || string(content) != "edited\n" {
+			t.Fatalf("modified emitted file should be kept: %q, %v", content, err)
+		}
+		if kept := a.GetKeptFiles(); len(kept) != 1 || kept[0] != manifest {
+			t.Errorf("GetKeptFiles() = %v, want [%s]", kept, manifest)
+		}
+		if removed := a.GetRemovedFiles(); len(removed) != 0 {
+			t.Errorf("GetRemovedFiles() = %v, want none", removed)
+		}
+	}
+
+	removed, err := a.CleanWithOptions(context.Background(), CleanOptions{})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if len(removed) != 0 || len(a.GetKeptFiles()) != 1 {
+		t.Errorf("CleanWithOptions() removed %v, kept %v; want the modified file kept", removed, a.GetKeptFiles())
+	}
+}
+
+func TestAdder_EmittedGoFile(t *testing.T) {
+	config := cleanTestConfig(t, "app")
+	config.BinaryName = "app"
+	registry := NewEmitter("registry", func(_ context.Context, root *CommandNode) ([]EmittedFile, error) {
+		content := generatedHeader + "\n\npackage registry\n\nconst Root = \"" + root.Command.Name + "\"\n"
+		return []EmittedFile{{Path: "registry/registry.go", Content: []byte(content)}}, nil
+	})
+	a := New(config, WithEmitter(registry))
+
+	// The emitted Go file carries the adder header but is not stale generated code
+	registryFile := filepath.Join(config.OutputDir, "registry", "registry.go")
+	for i := 0; i < 2; i++ {
+		if err := a.GenerateWithContext(context.Background()); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if _, err := os.Stat(registryFile); err != nil {
+			t.Fatalf("emitted Go file should be kept: %v", err)
+		}
+		if removed := a.GetRemovedFiles(); len(removed) != 0 {
+			t.Errorf("GetRemovedFiles() = %v, want none", removed)
+		}
+	}
+
+	removed, err := a.CleanWithOptions(context.Background(), CleanOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("CleanWithOptions() = %v, want no stale files", removed)
+	}
+	changes, err := a.Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Changes() = %+v, want none", changes)
+	}
+
+	// Removing every generated file lists the emitted Go file once
+	removed, err = a.CleanWithOptions(context.Background(), CleanOptions{All: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("CleanWithOptions() error = %v", err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("CleanWithOptions(All) = %v, want the generated file, the emitted file and the lockfile", removed)
+	}
+}
+
+func TestGenerator_EmitterPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "outside output", path: "../escape.txt", wantErr: "must be relative to the output directory"},
+		{name: "absolute", path: "/etc/escape.txt", wantErr: "must be relative to the output directory"},
+		{name: "generated code", path: "app_generated.go", wantErr: "already written by generated code"},
+		{name: "lockfile", path: LockFileName, wantErr: "already written by the lockfile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := cleanTestConfig(t, "app")
+			config.BinaryName = "app"
+			emitter := NewEmitter("bad", func(context.Context, *CommandNode) ([]EmittedFile, error) {
+				return []EmittedFile{{Path: tt.path, Content: []byte("x")}}, nil
+			})
+
+			err := New(config, WithEmitter(emitter)).GenerateWithContext(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "emitter bad") {
+				t.Errorf("Generate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package adder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes used by Execute
+// Codes up to ExitCodeValidation are reserved; declared command errors use higher codes
+const (
+	ExitCodeOK         = 0 // command succeeded
+	ExitCodeError      = 1 // unclassified failure
+	ExitCodeUsage      = 2 // invalid arguments, flags or subcommand
+	ExitCodeValidation = 3 // request failed validation
+)
+
+// MaxExitCode is the highest exit code a command may declare
+// Shells reserve 126 and above for their own failures
+const MaxExitCode = 125
+
+// ExitCoder is implemented by errors that carry a process exit code
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// UsageError reports that a command was invoked incorrectly
+type UsageError struct {
+	Err error
+}
+
+// NewUsageError creates a usage error from a formatted message
+func NewUsageError(format string, args ...interface{}) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+func (e *UsageError) ExitCode() int { return ExitCodeUsage }
+
+// ValidationError reports that a request failed validation
+type ValidationError struct {
+	Fields  []string // argument or flag names involved
+	Message string
+}
+
+// NewValidationError creates a validation error for the given fields
+func NewValidationError(message string, fields ...string) error {
+	return &ValidationError{Fields: fields, Message: message}
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+func (e *ValidationError) ExitCode() int { return ExitCodeValidation }
+
+// ExitError is a failure with a specific exit code
+// Generated constructors for declared command errors return an ExitError
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Errorf creates an exit error with the given code and formatted message
+func Errorf(code int, format string, args ...interface{}) error {
+	return &ExitError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+func (e *ExitError) ExitCode() int { return e.Code }
+
+// ExitCode returns the process exit code for an error
+// Nil maps to ExitCodeOK, errors without an ExitCoder in their chain to ExitCodeError
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	if isCobraUsageError(err) {
+		return ExitCodeUsage
+	}
+
+	return ExitCodeError
+}
+
+// Execute runs the root command and returns the exit code for its result
+// Flag parsing errors are reported as usage errors
+//
+//	func main() {
+//		os.Exit(adder.Execute(root))
+//	}
+func Execute(root *cobra.Command) int {
+	return ExitCode(ExecuteE(root))
+}
+
+// ExecuteE runs the root command as Execute does and returns its error instead of an exit code
+// Test helpers use it to get the same errors, and so exit codes, as the CLI
+func ExecuteE(root *cobra.Command) error {
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &UsageError{Err: err}
+	})
+
+	return root.Execute()
+}
+
+// cobraUsageErrors are message prefixes of the untyped errors cobra returns
+// for unknown commands, missing required flags and violated flag groups,
+// and of the flag parsing errors Execute otherwise wraps as usage errors
+var cobraUsageErrors = []string{
+	"unknown command",
+	"required flag(s)",
+	"if any flags in the group",
+	"at least one of the flags in the group",
+	"unknown flag",
+	"unknown shorthand flag",
+	"flag needs an argument",
+	"invalid argument",
+	"bad flag syntax",
+}
+
+// isCobraUsageError reports whether err is one of cobra's untyped usage errors
+func isCobraUsageError(err error) bool {
+	message := err.Error()
+	for _, prefix := range cobraUsageErrors {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package adder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitCodeOK},
+		{name: "plain error", err: errors.New("boom"), want: ExitCodeError},
+		{name: "usage error", err: NewUsageError("bad %s", "args"), want: ExitCodeUsage},
+		{name: "validation error", err: ValidateEnum("format", "xml", []string{"json"}), want: ExitCodeValidation},
+		{name: "exit error", err: Errorf(42, "not found"), want: 42},
+		{name: "wrapped exit error", err: fmt.Errorf("create: %w", Errorf(7, "conflict")), want: 7},
+		{name: "cobra required flag", err: errors.New(`required flag(s) "name" not set`), want: ExitCodeUsage},
+		{name: "cobra unknown command", err: errors.New(`unknown command "nope" for "app"`), want: ExitCodeUsage},
+		{name: "cobra unknown flag", err: errors.New("unknown flag: --nope"), want: ExitCodeUsage},
+		{name: "cobra unknown shorthand", err: errors.New("unknown shorthand flag: 'x' in -x"), want: ExitCodeUsage},
+		{name: "cobra missing flag value", err: errors.New("flag needs an argument: --name"), want: ExitCodeUsage},
+		{name: "cobra invalid flag value", err: errors.New(`invalid argument "x" for "--count" flag: strconv.ParseInt: parsing "x": invalid syntax`), want: ExitCodeUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	newRoot := func(runErr error) *cobra.Command {
+		root := &cobra.Command{Use: "app"}
+		sub := &cobra.Command{
+			Use:  "run",
+			Args: ExactArgsOrFromFile(1),
+			RunE: func(cmd *cobra.Command, args []string) error { return runErr },
+		}
+		sub.Flags().String("name", "", "name")
+		root.AddCommand(sub)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		return root
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		runErr error
+		want   int
+	}{
+		{name: "success", args: []string{"run", "a"}, want: ExitCodeOK},
+		{name: "unknown flag", args: []string{"run", "a", "--nope"}, want: ExitCodeUsage},
+		{name: "missing argument", args: []string{"run"}, want: ExitCodeUsage},
+		{name: "validation failure", args: []string{"run", "a"}, runErr: NewValidationError("missing required name", "name"), want: ExitCodeValidation},
+		{name: "declared error", args: []string{"run", "a"}, runErr: Errorf(9, "quota exceeded"), want: 9},
+		{name: "handler failure", args: []string{"run", "a"}, runErr: errors.New("boom"), want: ExitCodeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newRoot(tt.runErr)
+			root.SetArgs(tt.args)
+			if got := Execute(root); got != tt.want {
+				t.Errorf("Execute() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
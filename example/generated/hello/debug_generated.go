@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: hello/debug.md
 
 package hello
 
@@ -9,15 +10,15 @@ import (
 
 // DebugRequestFlags represents the flags for the debug command
 type DebugRequestFlags struct {
-	Trace bool `json:"trace"` // Enable detailed tracing
-	DumpConfig bool `json:"dumpConfig"` // Dump current configuration
-	TestEnum string `json:"testEnum" validate:"oneof=debug info warn error"` // Test enum validation
+	Trace      bool   `json:"trace"`                                           // Enable detailed tracing
+	DumpConfig bool   `json:"dumpConfig"`                                      // Dump current configuration
+	TestEnum   string `json:"testEnum" validate:"oneof=debug info warn error"` // Test enum validation
 }
 
 // DebugRequest represents the parameters for the debug command
 type DebugRequest struct {
-	Flags DebugRequestFlags `json:"flags"`
-	RawArguments []string `json:"raw_arguments"` // Raw command line arguments passed to the command
+	Flags        DebugRequestFlags `json:"flags"`
+	RawArguments []string          `json:"raw_arguments"` // Raw command line arguments passed to the command
 }
 
 // GetRawArguments implements the adder.Request interface
@@ -28,17 +29,83 @@ func (r *DebugRequest) GetRawArguments() []string {
 // Ensure DebugRequest implements adder.Request interface at compile time
 var _ adder.Request = (*DebugRequest)(nil)
 
+// Ensure DebugRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*DebugRequest)(nil)
+
+// DebugRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var DebugRequestValidateFunc func(req *DebugRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *DebugRequest) Validate() error {
+	if err := adder.ValidateEnum("test-enum", r.Flags.TestEnum, []string{"debug", "info", "warn", "error"}); err != nil {
+		return err
+	}
+
+	if DebugRequestValidateFunc != nil {
+		return DebugRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultDebugRequest returns a DebugRequest populated with the declared flag defaults
+func DefaultDebugRequest() *DebugRequest {
+	return &DebugRequest{
+		Flags: DebugRequestFlags{
+			Trace:      false,
+			DumpConfig: false,
+			TestEnum:   "info",
+		},
+	}
+}
+
+// DebugRequestBuilder builds DebugRequest values, e.g. for testing handlers
+type DebugRequestBuilder struct {
+	req DebugRequest
+}
+
+// NewDebugRequestBuilder creates a builder starting from DefaultDebugRequest
+func NewDebugRequestBuilder() *DebugRequestBuilder {
+	return &DebugRequestBuilder{req: *DefaultDebugRequest()}
+}
+
+// WithTrace sets the trace flag
+func (b *DebugRequestBuilder) WithTrace(value bool) *DebugRequestBuilder {
+	b.req.Flags.Trace = value
+	return b
+}
+
+// WithDumpConfig sets the dump-config flag
+func (b *DebugRequestBuilder) WithDumpConfig(value bool) *DebugRequestBuilder {
+	b.req.Flags.DumpConfig = value
+	return b
+}
+
+// WithTestEnum sets the test-enum flag
+func (b *DebugRequestBuilder) WithTestEnum(value string) *DebugRequestBuilder {
+	b.req.Flags.TestEnum = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *DebugRequestBuilder) Build() *DebugRequest {
+	req := b.req
+	return &req
+}
+
 // DebugHandler defines the function type for handling debug commands
 type DebugHandler func(cmd *cobra.Command, req *DebugRequest) error
 
 // NewDebugCommand creates a new debug command with the provided handler function
-func NewDebugCommand(handler DebugHandler) *cobra.Command {
+func NewDebugCommand(handler DebugHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
-		Use:     "debug",
-		Short:   "Debug greeting functionality (hidden)",
+		Use:    "debug",
+		Short:  "Debug greeting functionality (hidden)",
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDebug(cmd, args, handler)
+			return runDebug(cmd, args, handler, options)
 		},
 	}
 
@@ -49,29 +116,52 @@ func NewDebugCommand(handler DebugHandler) *cobra.Command {
 	cmd.Flags().Bool("dump-config", false, "Dump current configuration")
 	cmd.Flags().String("test-enum", "info", "Test enum validation")
 
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
 	return cmd
 }
 
 // runDebug handles argument and flag extraction
-func runDebug(cmd *cobra.Command, args []string, handler DebugHandler) error {
+func runDebug(cmd *cobra.Command, args []string, handler DebugHandler, options *adder.CommandOptions) error {
 	trace, _ := cmd.Flags().GetBool("trace")
 	dumpConfig, _ := cmd.Flags().GetBool("dump-config")
 	testEnum, _ := cmd.Flags().GetString("test-enum")
-	// Validate enum for test-enum
-	if err := adder.ValidateEnum("test-enum", testEnum, []string{"debug", "info", "warn", "error"}); err != nil {
-		return err
-	}
 
 	// Create request
 	req := &DebugRequest{
 		Flags: DebugRequestFlags{
-			Trace: trace,
+			Trace:      trace,
 			DumpConfig: dumpConfig,
-			TestEnum: testEnum,
+			TestEnum:   testEnum,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("trace") {
+			req.Flags.Trace = trace
+		}
+		if cmd.Flags().Changed("dump-config") {
+			req.Flags.DumpConfig = dumpConfig
+		}
+		if cmd.Flags().Changed("test-enum") {
+			req.Flags.TestEnum = testEnum
+		}
+	}
+	req.RawArguments = args
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*DebugRequest))
+	})(cmd, req)
 }
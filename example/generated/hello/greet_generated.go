@@ -1,4 +1,5 @@
 // Code generated by adder. DO NOT EDIT.
+// Source: hello/greet.md
 
 package hello
 
@@ -11,22 +12,23 @@ import (
 type GreetRequestArguments struct {
 	Name string `json:"name" validate:"required"` // Name of the person to greet
 }
+
 // GreetRequestFlags represents the flags for the greet [name] command
 type GreetRequestFlags struct {
-	Capitalize bool `json:"capitalize"` // Capitalize the greeting
-	AsciiArt string `json:"asciiArt" validate:"oneof=small big banner"` // ASCII art style for the greeting
-	Repeat int `json:"repeat"` // Number of times to repeat the greeting
-	Format string `json:"format" validate:"oneof=text json yaml"` // Output format for the greeting
-	Quiet bool `json:"quiet"` // Suppress extra output
-	Prefix string `json:"prefix"` // Prefix to add before the greeting
-	Languages []string `json:"languages"` // Additional languages to greet in
+	Capitalize bool     `json:"capitalize"`                                 // Capitalize the greeting
+	AsciiArt   string   `json:"asciiArt" validate:"oneof=small big banner"` // ASCII art style for the greeting
+	Repeat     int      `json:"repeat"`                                     // Number of times to repeat the greeting
+	Format     string   `json:"format" validate:"oneof=text json yaml"`     // Output format for the greeting
+	Quiet      bool     `json:"quiet"`                                      // Suppress extra output
+	Prefix     string   `json:"prefix"`                                     // Prefix to add before the greeting
+	Languages  []string `json:"languages"`                                  // Additional languages to greet in
 }
 
 // GreetRequest represents the parameters for the greet [name] command
 type GreetRequest struct {
-	Arguments GreetRequestArguments `json:"arguments"`
-	Flags GreetRequestFlags `json:"flags"`
-	RawArguments []string `json:"raw_arguments"` // Raw command line arguments passed to the command
+	Arguments    GreetRequestArguments `json:"arguments"`
+	Flags        GreetRequestFlags     `json:"flags"`
+	RawArguments []string              `json:"raw_arguments"` // Raw command line arguments passed to the command
 }
 
 // GetRawArguments implements the adder.Request interface
@@ -37,17 +39,122 @@ func (r *GreetRequest) GetRawArguments() []string {
 // Ensure GreetRequest implements adder.Request interface at compile time
 var _ adder.Request = (*GreetRequest)(nil)
 
+// Ensure GreetRequest implements adder.Validator interface at compile time
+var _ adder.Validator = (*GreetRequest)(nil)
+
+// GreetRequestValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var GreetRequestValidateFunc func(req *GreetRequest) error
+
+// Validate implements the adder.Validator interface
+func (r *GreetRequest) Validate() error {
+	if err := adder.ValidateRequired("name", r.Arguments.Name); err != nil {
+		return err
+	}
+	if err := adder.ValidateEnum("ascii-art", r.Flags.AsciiArt, []string{"small", "big", "banner"}); err != nil {
+		return err
+	}
+	if err := adder.ValidateEnum("format", r.Flags.Format, []string{"text", "json", "yaml"}); err != nil {
+		return err
+	}
+
+	if GreetRequestValidateFunc != nil {
+		return GreetRequestValidateFunc(r)
+	}
+	return nil
+}
+
+// DefaultGreetRequest returns a GreetRequest populated with the declared flag defaults
+func DefaultGreetRequest() *GreetRequest {
+	return &GreetRequest{
+		Flags: GreetRequestFlags{
+			Capitalize: false,
+			AsciiArt:   "small",
+			Repeat:     1,
+			Format:     "text",
+			Quiet:      false,
+			Prefix:     "Hello",
+		},
+	}
+}
+
+// GreetRequestBuilder builds GreetRequest values, e.g. for testing handlers
+type GreetRequestBuilder struct {
+	req GreetRequest
+}
+
+// NewGreetRequestBuilder creates a builder starting from DefaultGreetRequest
+func NewGreetRequestBuilder() *GreetRequestBuilder {
+	return &GreetRequestBuilder{req: *DefaultGreetRequest()}
+}
+
+// WithName sets the name argument
+func (b *GreetRequestBuilder) WithName(value string) *GreetRequestBuilder {
+	b.req.Arguments.Name = value
+	return b
+}
+
+// WithCapitalize sets the capitalize flag
+func (b *GreetRequestBuilder) WithCapitalize(value bool) *GreetRequestBuilder {
+	b.req.Flags.Capitalize = value
+	return b
+}
+
+// WithAsciiArt sets the ascii-art flag
+func (b *GreetRequestBuilder) WithAsciiArt(value string) *GreetRequestBuilder {
+	b.req.Flags.AsciiArt = value
+	return b
+}
+
+// WithRepeat sets the repeat flag
+func (b *GreetRequestBuilder) WithRepeat(value int) *GreetRequestBuilder {
+	b.req.Flags.Repeat = value
+	return b
+}
+
+// WithFormat sets the format flag
+func (b *GreetRequestBuilder) WithFormat(value string) *GreetRequestBuilder {
+	b.req.Flags.Format = value
+	return b
+}
+
+// WithQuiet sets the quiet flag
+func (b *GreetRequestBuilder) WithQuiet(value bool) *GreetRequestBuilder {
+	b.req.Flags.Quiet = value
+	return b
+}
+
+// WithPrefix sets the prefix flag
+func (b *GreetRequestBuilder) WithPrefix(value string) *GreetRequestBuilder {
+	b.req.Flags.Prefix = value
+	return b
+}
+
+// WithLanguages sets the languages flag
+func (b *GreetRequestBuilder) WithLanguages(value []string) *GreetRequestBuilder {
+	b.req.Flags.Languages = value
+	return b
+}
+
+// Build returns a copy of the built request
+func (b *GreetRequestBuilder) Build() *GreetRequest {
+	req := b.req
+	return &req
+}
+
 // GreetHandler defines the function type for handling greet [name] commands
 type GreetHandler func(cmd *cobra.Command, req *GreetRequest) error
 
 // NewGreetCommand creates a new greet [name] command with the provided handler function
-func NewGreetCommand(handler GreetHandler) *cobra.Command {
+func NewGreetCommand(handler GreetHandler, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
-		Use:     "greet [name]",
-		Short:   "Say hello to someone",
-		Args: cobra.ExactArgs(1),
+		Use:   "greet [name]",
+		Short: "Say hello to someone",
+		Args:  options.ExactArgsOrPrompt(1, 0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runGreet(cmd, args, handler)
+			return runGreet(cmd, args, handler, options)
 		},
 	}
 
@@ -62,12 +169,14 @@ func NewGreetCommand(handler GreetHandler) *cobra.Command {
 	cmd.Flags().String("prefix", "Hello", "Prefix to add before the greeting")
 	cmd.Flags().StringArray("languages", nil, "Additional languages to greet in")
 
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+
 	return cmd
 }
 
 // runGreet handles argument and flag extraction
-func runGreet(cmd *cobra.Command, args []string, handler GreetHandler) error {
-	name := args[0]
+func runGreet(cmd *cobra.Command, args []string, handler GreetHandler, options *adder.CommandOptions) error {
 	capitalize, _ := cmd.Flags().GetBool("capitalize")
 	asciiArt, _ := cmd.Flags().GetString("ascii-art")
 	repeat, _ := cmd.Flags().GetInt("repeat")
@@ -75,32 +184,74 @@ func runGreet(cmd *cobra.Command, args []string, handler GreetHandler) error {
 	quiet, _ := cmd.Flags().GetBool("quiet")
 	prefix, _ := cmd.Flags().GetString("prefix")
 	languages, _ := cmd.Flags().GetStringArray("languages")
-	// Validate enum for ascii-art
-	if err := adder.ValidateEnum("ascii-art", asciiArt, []string{"small", "big", "banner"}); err != nil {
-		return err
-	}
-	// Validate enum for format
-	if err := adder.ValidateEnum("format", format, []string{"text", "json", "yaml"}); err != nil {
-		return err
-	}
 
 	// Create request
 	req := &GreetRequest{
-		Arguments: GreetRequestArguments{
-			Name: name,
-		},
 		Flags: GreetRequestFlags{
 			Capitalize: capitalize,
-			AsciiArt: asciiArt,
-			Repeat: repeat,
-			Format: format,
-			Quiet: quiet,
-			Prefix: prefix,
-			Languages: languages,
+			AsciiArt:   asciiArt,
+			Repeat:     repeat,
+			Format:     format,
+			Quiet:      quiet,
+			Prefix:     prefix,
+			Languages:  languages,
 		},
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		if cmd.Flags().Changed("capitalize") {
+			req.Flags.Capitalize = capitalize
+		}
+		if cmd.Flags().Changed("ascii-art") {
+			req.Flags.AsciiArt = asciiArt
+		}
+		if cmd.Flags().Changed("repeat") {
+			req.Flags.Repeat = repeat
+		}
+		if cmd.Flags().Changed("format") {
+			req.Flags.Format = format
+		}
+		if cmd.Flags().Changed("quiet") {
+			req.Flags.Quiet = quiet
+		}
+		if cmd.Flags().Changed("prefix") {
+			req.Flags.Prefix = prefix
+		}
+		if cmd.Flags().Changed("languages") {
+			req.Flags.Languages = languages
+		}
+	}
+	if len(args) > 0 {
+		req.Arguments.Name = args[0]
+	}
+	req.RawArguments = args
+
+	// One prompter per execution, so answers typed ahead are kept between prompts
+	prompter := options.PrompterFor(cmd)
+
+	// Prompt for missing values when attached to a terminal
+	if prompter != nil {
+		if req.Arguments.Name == "" {
+			value, err := prompter.String("Who should we greet")
+			if err != nil {
+				return err
+			}
+			req.Arguments.Name = value
+		}
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*GreetRequest))
+	})(cmd, req)
 }
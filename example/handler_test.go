@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/jrschumacher/adder"
 	"github.com/jrschumacher/adder/example/generated"
 	"github.com/jrschumacher/adder/example/generated/hello"
 	"github.com/spf13/cobra"
@@ -102,7 +105,8 @@ func TestGreetHandler_Integration(t *testing.T) {
 	// Create the parent hello command
 	helloCmd := generated.NewHelloCommand(func(cmd *cobra.Command, req *generated.HelloRequest) error {
 		return cmd.Help()
-	})
+	}, setupHello)
+	t.Cleanup(func() { adder.UnregisterSetup(helloCmd) })
 
 	// Add the greet subcommand
 	greetCmd := hello.NewGreetCommand(func(cmd *cobra.Command, req *hello.GreetRequest) error {
@@ -150,6 +154,48 @@ func TestGreetHandler_Integration(t *testing.T) {
 	}
 }
 
+func TestGreetHandler_WithBuilder(t *testing.T) {
+	// Start from the declared defaults and override what the test needs
+	req := hello.NewGreetRequestBuilder().
+		WithName("Frank").
+		WithRepeat(2).
+		WithFormat("json").
+		Build()
+
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Built request is invalid: %v", err)
+	}
+	if req.Flags.Prefix != "Hello" {
+		t.Errorf("Expected default prefix Hello, got %q", req.Flags.Prefix)
+	}
+	if err := handleGreet(&cobra.Command{}, req); err != nil {
+		t.Errorf("handleGreet() error = %v", err)
+	}
+}
+
+func TestGreetHandler_Prompt(t *testing.T) {
+	// Inject a prompter so the missing name is asked for without a terminal
+	var prompts bytes.Buffer
+	prompter := adder.NewPrompter(strings.NewReader("Erin\n"), &prompts)
+
+	var greeted string
+	greetCmd := hello.NewGreetCommand(func(cmd *cobra.Command, req *hello.GreetRequest) error {
+		greeted = req.Arguments.Name
+		return nil
+	}, adder.WithPrompter(prompter))
+	greetCmd.SetArgs([]string{})
+
+	if err := greetCmd.Execute(); err != nil {
+		t.Fatalf("Command execution failed: %v", err)
+	}
+	if greeted != "Erin" {
+		t.Errorf("Expected prompted name Erin, got %q", greeted)
+	}
+	if !strings.Contains(prompts.String(), "Who should we greet: ") {
+		t.Errorf("Expected prompt, got %q", prompts.String())
+	}
+}
+
 // Example of testing with dependency injection
 type MockGreeter struct {
 	calls []string
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/jrschumacher/adder"
 	"github.com/jrschumacher/adder/example/generated"
 	"github.com/jrschumacher/adder/example/generated/hello"
 	"github.com/spf13/cobra"
@@ -24,7 +25,7 @@ in docs/man/hello.md and demonstrates type-safe CLI generation.`,
 	helloCmd := generated.NewHelloCommand(func(cmd *cobra.Command, req *generated.HelloRequest) error {
 		// Show help when no subcommand is provided
 		return cmd.Help()
-	})
+	}, setupHello)
 	
 	// Add greet subcommand
 	greetCmd := hello.NewGreetCommand(func(cmd *cobra.Command, req *hello.GreetRequest) error {
@@ -40,10 +41,16 @@ in docs/man/hello.md and demonstrates type-safe CLI generation.`,
 	
 	rootCmd.AddCommand(helloCmd)
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Exit with the code of the returned error (e.g. 2 for usage errors)
+	os.Exit(adder.Execute(rootCmd))
+}
+
+// setupHello runs before the hello command and each of its subcommands
+func setupHello(cmd *cobra.Command, req *generated.HelloRequest) (func() error, error) {
+	if req.PersistentFlags.Verbose {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using config %s\n", req.PersistentFlags.Config)
 	}
+	return nil, nil
 }
 
 // handleGreet implements the business logic for the greet command
@@ -0,0 +1,146 @@
+package adder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// formatGoSource removes unused imports from generated Go source and formats it like gofmt
+// A parse error names the offending line of the generated code
+func formatGoSource(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, describeParseError(src, err)
+	}
+
+	pruneImports(file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("formatting: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// describeParseError adds the generated line the first parse error points at
+func describeParseError(src []byte, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return err
+	}
+
+	first := list[0]
+	lines := strings.Split(string(src), "\n")
+	if first.Pos.Line < 1 || first.Pos.Line > len(lines) {
+		return err
+	}
+	return fmt.Errorf("%w\n\t%d | %s", err, first.Pos.Line, strings.TrimSpace(lines[first.Pos.Line-1]))
+}
+
+// majorVersionSuffix matches import paths ending in a major version, e.g. gopkg.in/yaml.v2 or /v3
+var majorVersionSuffix = regexp.MustCompile(`(^v\d+$)|(\.v\d+$)`)
+
+// pruneImports removes imports the file does not reference
+// Blank, dot and imports whose package name cannot be told from the path are kept
+func pruneImports(file *ast.File) {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	unused := func(spec *ast.ImportSpec) bool {
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return false
+			}
+			name = path.Base(importPath)
+			if majorVersionSuffix.MatchString(name) || strings.ContainsAny(name, ".-") {
+				return false
+			}
+		}
+		return name != "_" && name != "." && !used[name]
+	}
+
+	var decls []ast.Decl
+	var imports []*ast.ImportSpec
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+
+		var specs []ast.Spec
+		for _, spec := range gen.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			if unused(importSpec) {
+				continue
+			}
+			specs = append(specs, spec)
+			imports = append(imports, importSpec)
+		}
+		if len(specs) == 0 {
+			continue
+		}
+		gen.Specs = specs
+		decls = append(decls, gen)
+	}
+	file.Decls = decls
+	file.Imports = imports
+}
+
+// writeFileAtomic writes content to a temporary file next to path and renames it into place
+// An interrupted run leaves either the old or the new file, never a partially written one
+func writeFileAtomic(path string, content []byte, perm os.FileMode) error {
+	// The leading dot keeps the Go tool and adder's own scans away from the temporary file
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if tmpName != "" {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+
+	tmpName = ""
+	return nil
+}
@@ -0,0 +1,108 @@
+package adder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatGoSource(t *testing.T) {
+	src := `// Code generated by adder. DO NOT EDIT.
+
+package generated
+
+import (
+	"fmt"
+	"strings"
+	_ "embed"
+	"gopkg.in/yaml.v2"
+	"github.com/spf13/cobra"
+)
+
+type Request struct {
+	Name string
+	Count   int
+}
+
+func run(cmd *cobra.Command) string { return fmt.Sprint(cmd.Use) }
+`
+
+	got, err := formatGoSource("test_generated.go", []byte(src))
+	if err != nil {
+		t.Fatalf("formatGoSource() error = %v", err)
+	}
+
+	for _, want := range []string{`"fmt"`, `_ "embed"`, `"gopkg.in/yaml.v2"`, `"github.com/spf13/cobra"`, "\tName  string\n\tCount int\n"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("formatted source missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), `"strings"`) {
+		t.Errorf("unused import was not removed:\n%s", got)
+	}
+}
+
+func TestFormatGoSource_ParseError(t *testing.T) {
+	src := "package generated\n\nfunc broken( {\n}\n"
+
+	_, err := formatGoSource("broken_generated.go", []byte(src))
+	if err == nil {
+		t.Fatal("formatGoSource() expected an error for invalid Go")
+	}
+	if !strings.Contains(err.Error(), "broken_generated.go:3") || !strings.Contains(err.Error(), "3 | func broken( {") {
+		t.Errorf("error should point at the generated line: %v", err)
+	}
+}
+
+func TestGenerator_InvalidGeneratedCode(t *testing.T) {
+	original := Templates.Command
+	Templates.Command = "\nfunc {{.StructName}}( {\n"
+	defer func() { Templates.Command = original }()
+
+	cmd, err := NewParser(DefaultConfig()).ParseContent("---\ntitle: Greet\ncommand:\n  name: greet\n---\n", "hello/greet.md")
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+
+	_, err = NewGenerator(DefaultConfig()).generateFileContent([]*Command{cmd})
+	if err == nil || !strings.Contains(err.Error(), "generated code for hello/greet.md is not valid Go") {
+		t.Errorf("generateFileContent() error = %v, want a pointer to hello/greet.md", err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out_generated.go")
+
+	for _, content := range []string{"first\n", "second\n"} {
+		if err := writeFileAtomic(path, []byte(content), 0644); err != nil {
+			t.Fatalf("writeFileAtomic() error = %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != content {
+			t.Errorf("content = %q, %v; want %q", got, err, content)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+
+	// Errors creating the temporary file are reported
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "out.go"), []byte("x"), 0644); err == nil {
+		t.Error("writeFileAtomic() expected an error for a missing directory")
+	}
+}
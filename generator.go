@@ -3,12 +3,15 @@ package adder
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	"text/template"
+	"sync"
 )
 
 // Generator handles code generation from parsed commands
@@ -16,8 +19,18 @@ type Generator struct {
 	config       *Config
 	parser       *Parser
 	commands     []*Command
-	force        bool // Force regeneration of all files
-	skippedFiles int  // Number of files skipped during incremental generation
+	force        bool     // Force regeneration of all files
+	keepStale    bool     // Keep generated files whose markdown source no longer exists
+	skippedFiles int      // Number of files skipped during incremental generation
+	removedFiles []string // Stale generated files removed during generation
+	keptFiles    []string // Stale emitted files kept because they were modified
+	emittedFiles int      // Number of files written by emitters
+	emitters     []Emitter
+	output       OutputFS // Where generated files are written, the output directory by default
+
+	templatesOnce sync.Once // Loads the templates on first use
+	templateSet   *templateSet
+	templatesErr  error
 }
 
 // NewGenerator creates a new generator instance
@@ -25,13 +38,15 @@ func NewGenerator(config *Config) *Generator {
 	return &Generator{
 		config: config,
 		parser: NewParser(config),
+		output: DirOutputFS(config.OutputDir),
 	}
 }
 
 // Generate processes the input directory and generates code
-func (g *Generator) Generate(_ context.Context, inputFS fs.FS) error {
+// Files are parsed and rendered concurrently; generation stops when ctx is cancelled
+func (g *Generator) Generate(ctx context.Context, inputFS fs.FS) error {
 	// Parse all commands from input directory
-	commands, err := g.parser.ParseDirectory(inputFS)
+	commands, err := g.parser.ParseDirectoryContext(ctx, inputFS)
 	if err != nil {
 		return fmt.Errorf("parsing directory: %w", err)
 	}
@@ -40,40 +55,191 @@ func (g *Generator) Generate(_ context.Context, inputFS fs.FS) error {
 
 	// Group commands by output file
 	fileGroups := g.groupCommandsByFile()
+	filenames := sortedFilenames(fileGroups)
+
+	// Outputs whose recorded inputs are unchanged are skipped without rendering
+	state, err := g.newLockState()
+	if err != nil {
+		return err
+	}
 
 	// Generate code for each file
-	skippedCount := 0
-	for filename, cmds := range fileGroups {
-		// Check if any source file for this output file needs regeneration
-		needsRegeneration := false
-		for _, cmd := range cmds {
-			sourceFile := g.getSourceFilePath(cmd)
-			should, err := g.shouldRegenerateFile(sourceFile, filename)
-			if err != nil {
-				return fmt.Errorf("checking if %s needs regeneration: %w", filename, err)
-			}
-			if should {
-				needsRegeneration = true
-				break
-			}
+	entries := make([]lockEntry, len(filenames))
+	skipped := make([]bool, len(filenames))
+	err = forEachParallel(ctx, len(filenames), func(i int) error {
+		entry, skip, err := g.generateOutput(inputFS, filenames[i], fileGroups[filenames[i]], state)
+		if err != nil {
+			return err
 		}
+		entries[i], skipped[i] = entry, skip
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		if !needsRegeneration {
+	skippedCount := 0
+	for _, skip := range skipped {
+		if skip {
 			skippedCount++
-			continue
 		}
+	}
 
-		if err := g.generateFile(filename, cmds); err != nil {
-			return fmt.Errorf("generating %s: %w", filename, err)
-		}
+	// Emitters see the whole command tree and write their artifacts next to the Go code
+	emitted, err := g.emit(ctx)
+	if err != nil {
+		return err
+	}
+	emittedEntries, emittedSkipped, err := g.writeEmitted(emitted)
+	if err != nil {
+		return err
+	}
+	skippedCount += emittedSkipped
+	g.emittedFiles = len(emitted)
+
+	// Modified emitted files stay in the lockfile, so they are checked again instead of becoming unknown
+	emittedStale, kept, keptEntries := g.staleEmitted(state.previous, emitted)
+	if err := g.writeLock(append(append(entries, emittedEntries...), keptEntries...)); err != nil {
+		return err
 	}
 
 	// Update stats to include skipped files
 	g.skippedFiles = skippedCount
 
+	// Remove outputs of deleted or renamed markdown files
+	g.removedFiles = nil
+	g.keptFiles = nil
+	if !g.keepStale {
+		stale, err := g.staleFiles(state.previous, emitted)
+		if err != nil {
+			return fmt.Errorf("finding stale files: %w", err)
+		}
+		stale = append(stale, emittedStale...)
+		g.keptFiles = kept
+		if err := g.removeFiles(stale); err != nil {
+			return err
+		}
+		g.removedFiles = stale
+	}
+
 	return nil
 }
 
+// generateOutput brings a single output file up to date and returns its lockfile entry
+// It reports whether the file was left untouched, because its inputs or its content are unchanged
+func (g *Generator) generateOutput(inputFS fs.FS, filename string, cmds []*Command, state *lockState) (lockEntry, bool, error) {
+	entry, err := g.lockEntryFor(inputFS, filename, cmds, state)
+	if err != nil {
+		return lockEntry{}, false, fmt.Errorf("checking if %s needs regeneration: %w", filename, err)
+	}
+
+	current, err := g.readOutput(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return lockEntry{}, false, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	exists := err == nil
+
+	previous, ok := state.previous[entry.Output]
+	if !g.force && ok && exists && previous.sameInputs(entry) && previous.Hash == hashContent(current) {
+		return previous, true, nil
+	}
+
+	content, err := g.generateFileContent(cmds)
+	if err != nil {
+		return lockEntry{}, false, fmt.Errorf("generating %s: %w", filename, err)
+	}
+	entry.Hash = hashContent([]byte(content))
+
+	// Identical content is not rewritten, so modification times only change with the code
+	if !g.force && exists && string(current) == content {
+		return entry, true, nil
+	}
+	if err := g.writeFile(filename, content); err != nil {
+		return lockEntry{}, false, fmt.Errorf("generating %s: %w", filename, err)
+	}
+	return entry, false, nil
+}
+
+// Changes returns the generated files whose content would change, without writing anything
+// Every output is rendered and compared with the file on disk, regardless of modification times
+// Generated files without a markdown source are reported as deletions
+func (g *Generator) Changes(ctx context.Context, inputFS fs.FS) ([]FileChange, error) {
+	commands, err := g.parser.ParseDirectoryContext(ctx, inputFS)
+	if err != nil {
+		return nil, fmt.Errorf("parsing directory: %w", err)
+	}
+
+	g.commands = commands
+
+	fileGroups := g.groupCommandsByFile()
+	filenames := sortedFilenames(fileGroups)
+
+	// A nil entry means the file is up to date
+	pending := make([]*FileChange, len(filenames))
+	err = forEachParallel(ctx, len(filenames), func(i int) error {
+		filename := filenames[i]
+		content, err := g.generateFileContent(fileGroups[filename])
+		if err != nil {
+			return fmt.Errorf("generating %s: %w", filename, err)
+		}
+
+		current, err := g.readOutput(filename)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			pending[i] = &FileChange{Path: filename, Action: ChangeCreate, New: []byte(content)}
+		case err != nil:
+			return fmt.Errorf("reading %s: %w", filename, err)
+		case string(current) != content:
+			pending[i] = &FileChange{Path: filename, Action: ChangeUpdate, Old: current, New: []byte(content)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	for _, change := range pending {
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	emitted, err := g.emit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, out := range emitted {
+		current, err := g.readOutput(out.path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			changes = append(changes, FileChange{Path: out.path, Action: ChangeCreate, New: out.content})
+		case err != nil:
+			return nil, fmt.Errorf("reading %s: %w", out.path, err)
+		case string(current) != string(out.content):
+			changes = append(changes, FileChange{Path: out.path, Action: ChangeUpdate, Old: current, New: out.content})
+		}
+	}
+
+	previous := g.readLock()
+	stale, err := g.staleFiles(previous, emitted)
+	if err != nil {
+		return nil, fmt.Errorf("finding stale files: %w", err)
+	}
+	emittedStale, _, _ := g.staleEmitted(previous, emitted)
+	stale = append(stale, emittedStale...)
+	for _, filename := range stale {
+		current, err := g.readOutput(filename)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filename, err)
+		}
+		changes = append(changes, FileChange{Path: filename, Action: ChangeDelete, Old: current})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
 // groupCommandsByFile groups commands by their output file
 func (g *Generator) groupCommandsByFile() map[string][]*Command {
 	groups := make(map[string][]*Command)
@@ -86,6 +252,16 @@ func (g *Generator) groupCommandsByFile() map[string][]*Command {
 	return groups
 }
 
+// sortedFilenames returns the output files of grouped commands in a stable order
+func sortedFilenames(groups map[string][]*Command) []string {
+	filenames := make([]string, 0, len(groups))
+	for filename := range groups {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	return filenames
+}
+
 // getOutputFilename returns the output filename for a command
 func (g *Generator) getOutputFilename(cmd *Command) string {
 	// FilePath is already relative to InputDir from filesystem walk
@@ -105,27 +281,34 @@ func (g *Generator) getOutputFilename(cmd *Command) string {
 	return filepath.Join(g.config.OutputDir, dir, filename)
 }
 
-// generateFile generates code for a single output file
-func (g *Generator) generateFile(filename string, commands []*Command) error {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
-		return fmt.Errorf("creating output directory: %w", err)
-	}
-
-	// Generate file content
-	content, err := g.generateFileContent(commands)
+// writeFile writes generated content to the output FS, creating the output directory if needed
+func (g *Generator) writeFile(filename, content string) error {
+	name, err := g.outputName(filename)
 	if err != nil {
-		return fmt.Errorf("generating content: %w", err)
+		return err
 	}
 
-	// Write to file
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	if err := g.output.WriteFile(name, []byte(content), 0644); err != nil {
 		return fmt.Errorf("writing file: %w", err)
 	}
 
 	return nil
 }
 
+// RenderCommand returns the generated Go file for a single command without writing anything
+// The command is rendered as if it had a markdown file of its own
+func (g *Generator) RenderCommand(cmd *Command) ([]byte, error) {
+	// Commands built in code have not been validated by the parser
+	if err := g.parser.validateCommand(cmd); err != nil {
+		return nil, err
+	}
+	content, err := g.generateFileContent([]*Command{cmd})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
 // generateFileContent generates the content for a file
 func (g *Generator) generateFileContent(commands []*Command) (string, error) {
 	var buf bytes.Buffer
@@ -140,14 +323,25 @@ func (g *Generator) generateFileContent(commands []*Command) (string, error) {
 	}
 
 	// Generate package header
+	// Record the markdown sources, so a reader can find what to edit
+	sources := make([]string, len(commands))
+	for i, cmd := range commands {
+		sources[i] = filepath.ToSlash(cmd.FilePath)
+	}
+
 	packageData := struct {
 		Package string
+		Sources string
 	}{
 		Package: packageName,
+		Sources: strings.Join(sources, ", "),
 	}
 
-	tmpl := template.Must(template.New("package").Parse(Templates.Package))
-	if err := tmpl.Execute(&buf, packageData); err != nil {
+	templates, err := g.templates()
+	if err != nil {
+		return "", err
+	}
+	if err := templates.root.ExecuteTemplate(&buf, packageTemplateName, packageData); err != nil {
 		return "", fmt.Errorf("executing package template: %w", err)
 	}
 
@@ -160,39 +354,223 @@ func (g *Generator) generateFileContent(commands []*Command) (string, error) {
 		buf.WriteString(cmdContent)
 	}
 
-	return buf.String(), nil
+	// Generated code must parse; point at the markdown, since that is what the user can fix
+	filename := "generated.go"
+	if len(commands) > 0 {
+		filename = filepath.Base(g.getOutputFilename(commands[0]))
+	}
+	formatted, err := formatGoSource(filename, buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("generated code for %s is not valid Go: %w", strings.Join(sources, ", "), err)
+	}
+
+	return string(formatted), nil
 }
 
 // generateCommand generates code for a single command
+// Custom templates other than the built-in ones and their blocks are rendered after it
 func (g *Generator) generateCommand(cmd *Command) (string, error) {
+	templates, err := g.templates()
+	if err != nil {
+		return "", err
+	}
+
 	// Prepare template data
 	data := struct {
-		Command      *Command
-		StructName   string
-		HandlerName  string
-		MethodName   string
-		FunctionName string
+		Command             *Command
+		StructName          string
+		HandlerName         string
+		MethodName          string
+		FunctionName        string
+		ResponseName        string
+		FlagRules           []flagRule
+		Prompts             []promptField
+		PromptArgs          int
+		RequiredFlags       []RequiredFlag
+		RequiredPromptFlags []RequiredFlag
 	}{
-		Command:      cmd,
-		StructName:   g.parser.GetStructName(cmd),
-		HandlerName:  g.parser.GetHandlerName(cmd),
-		MethodName:   g.parser.GetMethodName(cmd),
-		FunctionName: g.parser.GetFunctionName(cmd),
+		Command:             cmd,
+		StructName:          g.parser.GetStructName(cmd),
+		HandlerName:         g.parser.GetHandlerName(cmd),
+		MethodName:          g.parser.GetMethodName(cmd),
+		FunctionName:        g.parser.GetFunctionName(cmd),
+		ResponseName:        g.parser.GetResponseName(cmd),
+		FlagRules:           buildFlagRules(cmd),
+		Prompts:             buildPrompts(cmd),
+		PromptArgs:          minPromptArgs(cmd),
+		RequiredFlags:       buildRequiredFlags(cmd, false),
+		RequiredPromptFlags: buildRequiredFlags(cmd, true),
 	}
 
-	// Execute template
-	tmpl := template.Must(template.New("command").
-		Funcs(TemplateFunctions()).
-		Parse(Templates.Command))
-
+	// Execute templates
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := templates.root.ExecuteTemplate(&buf, commandTemplateName, data); err != nil {
 		return "", fmt.Errorf("executing template: %w", err)
 	}
 
+	for _, name := range templates.extras {
+		var extra bytes.Buffer
+		if err := templates.root.ExecuteTemplate(&extra, name, data); err != nil {
+			return "", fmt.Errorf("executing template %s: %w", name, err)
+		}
+		// Templates that only define blocks or render nothing for this command add no code
+		if strings.TrimSpace(extra.String()) != "" {
+			buf.WriteString("\n")
+			buf.Write(extra.Bytes())
+		}
+	}
+
 	return buf.String(), nil
 }
 
+// flagRule represents a flag rule check emitted into the generated code
+type flagRule struct {
+	CobraMethod string      // cobra.Command method registering the flag group
+	Validator   string      // adder function validating the rule on a request
+	Fields      []ruleField // fields participating in the rule, declaring flag first
+}
+
+// ruleField represents a flag participating in a flag rule
+type ruleField struct {
+	Name          string // flag name
+	SetExpression string // Go expression reporting whether the request field is set
+}
+
+// buildFlagRules collects the flag rules declared on a command's flags
+func buildFlagRules(cmd *Command) []flagRule {
+	fields := make(map[string]ruleField)
+	for _, flag := range cmd.Flags {
+		fields[flag.Name] = ruleField{Name: flag.Name, SetExpression: flag.GetIsSetExpression("r.Flags." + pascalCase(flag.Name))}
+	}
+	for _, flag := range cmd.PersistentFlags {
+		fields[flag.Name] = ruleField{Name: flag.Name, SetExpression: flag.GetIsSetExpression("r.PersistentFlags." + pascalCase(flag.Name))}
+	}
+
+	var rules []flagRule
+	for _, flags := range [][]Flag{cmd.Flags, cmd.PersistentFlags} {
+		for _, flag := range flags {
+			add := func(cobraMethod, validator string, names []string) {
+				if len(names) == 0 {
+					return
+				}
+				rule := flagRule{CobraMethod: cobraMethod, Validator: validator, Fields: []ruleField{fields[flag.Name]}}
+				for _, name := range names {
+					rule.Fields = append(rule.Fields, fields[name])
+				}
+				rules = append(rules, rule)
+			}
+			add("MarkFlagsMutuallyExclusive", "ValidateMutuallyExclusive", flag.MutuallyExclusive)
+			add("MarkFlagsRequiredTogether", "ValidateRequiredTogether", flag.RequiredTogether)
+			add("MarkFlagsOneRequired", "ValidateOneRequired", flag.OneRequired)
+		}
+	}
+
+	return rules
+}
+
+// buildRequiredFlags collects the required flags that prompt when missing, or the other ones
+// The others are checked once the request file is merged, the ones that prompt only when the
+// command cannot prompt
+func buildRequiredFlags(cmd *Command, prompt bool) []RequiredFlag {
+	var required []RequiredFlag
+	for _, set := range []struct {
+		key   string
+		flags []Flag
+	}{{"flags", cmd.Flags}, {"persistent_flags", cmd.PersistentFlags}} {
+		for _, flag := range set.flags {
+			if flag.Required && flag.Prompt == prompt {
+				required = append(required, RequiredFlag{Name: flag.Name, Key: set.key + "." + fieldName(flag.Name)})
+			}
+		}
+	}
+	return required
+}
+
+// promptField represents a missing value the generated command prompts for
+type promptField struct {
+	Name            string // argument or flag name
+	Label           string // prompt label
+	Field           string // request field receiving the answer
+	FlagSet         string // "Flags" or "PersistentFlags" for flags, empty for arguments
+	UnsetExpression string // Go expression reporting whether the request field is missing
+	Call            string // Prompter method call, e.g. Select("Format", []string{"json", "yaml"})
+}
+
+// buildPrompts collects the arguments and flags that prompt when missing, in declaration order
+func buildPrompts(cmd *Command) []promptField {
+	var prompts []promptField
+	for _, arg := range cmd.Arguments {
+		if !arg.Prompt {
+			continue
+		}
+		field := "req.Arguments." + pascalCase(arg.Name)
+		prompt := promptField{Name: arg.Name, Label: arg.GetPromptLabel(), Field: field, UnsetExpression: field + ` == ""`}
+		prompt.Call = fmt.Sprintf("String(%s)", strconv.Quote(prompt.Label))
+		if arg.Sensitive {
+			prompt.Call = fmt.Sprintf("Password(%s)", strconv.Quote(prompt.Label))
+		}
+		prompts = append(prompts, prompt)
+	}
+
+	for _, set := range []struct {
+		name  string
+		flags []Flag
+	}{{"Flags", cmd.Flags}, {"PersistentFlags", cmd.PersistentFlags}} {
+		for _, flag := range set.flags {
+			if !flag.Prompt {
+				continue
+			}
+			field := "req." + set.name + "." + pascalCase(flag.Name)
+			prompt := promptField{Name: flag.Name, Label: flag.GetPromptLabel(), Field: field, FlagSet: set.name, UnsetExpression: flag.GetIsUnsetExpression(field)}
+			label := strconv.Quote(prompt.Label)
+			switch {
+			case flag.Sensitive:
+				prompt.Call = fmt.Sprintf("Password(%s)", label)
+			case len(flag.Enum) > 0:
+				prompt.Call = fmt.Sprintf("Select(%s, []string{%s})", label, quoteList(flag.Enum))
+			case flag.Type == TypeBool:
+				prompt.Call = fmt.Sprintf("Confirm(%s, %s)", label, flag.GetDefaultValue())
+			case flag.Type == TypeInt:
+				prompt.Call = fmt.Sprintf("Int(%s)", label)
+			case flag.Type == TypeStringArray:
+				prompt.Call = fmt.Sprintf("Strings(%s)", label)
+			default:
+				prompt.Call = fmt.Sprintf("String(%s)", label)
+			}
+			prompts = append(prompts, prompt)
+		}
+	}
+
+	return prompts
+}
+
+// quoteList formats values as a comma separated list of Go string literals
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// minPromptArgs returns the number of positional arguments required when the command
+// can prompt for trailing arguments, or -1 if no argument prompts
+func minPromptArgs(cmd *Command) int {
+	prompts := false
+	min := 0
+	for i, arg := range cmd.Arguments {
+		if arg.Prompt {
+			prompts = true
+		} else {
+			min = i + 1
+		}
+	}
+	if !prompts {
+		return -1
+	}
+	return min
+}
+
 // GenerateFromDirectory is a convenience function to generate from a directory path
 func GenerateFromDirectory(_, inputDir string) error {
 	// Load config (for now use default)
@@ -239,6 +617,9 @@ func (g *Generator) GetStats() map[string]int {
 	stats := make(map[string]int)
 	stats["total_commands"] = len(g.commands)
 	stats["skipped_files"] = g.skippedFiles
+	stats["removed_files"] = len(g.removedFiles)
+	stats["kept_files"] = len(g.keptFiles)
+	stats["emitted_files"] = g.emittedFiles
 
 	for _, cmd := range g.commands {
 		stats["total_arguments"] += len(cmd.Arguments)
@@ -253,39 +634,18 @@ func (g *Generator) SetForceRegeneration(force bool) {
 	g.force = force
 }
 
-// shouldRegenerateFile checks if a file needs to be regenerated based on modification times
-func (g *Generator) shouldRegenerateFile(sourceFile, outputFile string) (bool, error) {
-	if g.force {
-		return true, nil
-	}
-
-	// Check if this is a generated file (has the configured suffix)
-	if strings.HasSuffix(outputFile, g.config.GeneratedFileSuffix) {
-		// Generated files should always be regenerated automatically
-		// since they're meant to be auto-generated and overwritten
-		return true, nil
-	}
-
-	// Check if output file exists
-	outputInfo, err := os.Stat(outputFile)
-	if os.IsNotExist(err) {
-		return true, nil // Output doesn't exist, need to generate
-	}
-	if err != nil {
-		return false, fmt.Errorf("checking output file %s: %w", outputFile, err)
-	}
-
-	// Check source file modification time
-	sourceInfo, err := os.Stat(sourceFile)
-	if err != nil {
-		return false, fmt.Errorf("checking source file %s: %w", sourceFile, err)
-	}
+// SetKeepStale sets whether generation keeps files whose markdown source no longer exists
+func (g *Generator) SetKeepStale(keep bool) {
+	g.keepStale = keep
+}
 
-	// Regenerate if source is newer than output
-	return sourceInfo.ModTime().After(outputInfo.ModTime()), nil
+// RemovedFiles returns the stale files removed by the last generation
+func (g *Generator) RemovedFiles() []string {
+	return g.removedFiles
 }
 
-// getSourceFilePath returns the full path to the source markdown file
-func (g *Generator) getSourceFilePath(cmd *Command) string {
-	return filepath.Join(g.config.InputDir, cmd.FilePath)
+// KeptFiles returns the stale emitted files the last generation or clean did not remove,
+// because they were modified since they were emitted
+func (g *Generator) KeptFiles() []string {
+	return g.keptFiles
 }
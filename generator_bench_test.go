@@ -0,0 +1,116 @@
+package adder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+// benchmarkTree returns a synthetic input directory of 50 command groups with 100 subcommands each
+func benchmarkTree() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for group := 0; group < 50; group++ {
+		name := fmt.Sprintf("group%02d", group)
+		fsys[name+"/"+name+".md"] = &fstest.MapFile{Data: []byte(fmt.Sprintf(`---
+title: Group %d
+command:
+  name: %s
+  persistent_flags:
+    - name: profile
+      type: string
+      default: default
+---
+
+# Group %d
+`, group, name, group))}
+
+		for cmd := 0; cmd < 100; cmd++ {
+			fsys[fmt.Sprintf("%s/cmd%03d.md", name, cmd)] = &fstest.MapFile{Data: []byte(fmt.Sprintf(`---
+title: Command %d
+command:
+  name: cmd%03d [target]
+  arguments:
+    - name: target
+      description: Target to act on
+      required: true
+  flags:
+    - name: count
+      shorthand: c
+      type: int
+      default: 1
+    - name: format
+      type: string
+      enum: [json, yaml, text]
+      default: text
+    - name: labels
+      type: stringArray
+    - name: dry-run
+      type: bool
+---
+
+# Command %d
+
+Does something to the target.
+`, cmd, cmd, cmd))}
+		}
+	}
+	return fsys
+}
+
+func benchmarkConfig(b *testing.B) *Config {
+	config := DefaultConfig()
+	config.BinaryName = "bench"
+	config.InputDir = "."
+	config.OutputDir = b.TempDir()
+	return config
+}
+
+func BenchmarkParseDirectory(b *testing.B) {
+	fsys := benchmarkTree()
+	parser := NewParser(benchmarkConfig(b))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		commands, err := parser.ParseDirectoryContext(context.Background(), fsys)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(commands) != 5050 {
+			b.Fatalf("parsed %d commands, want 5050", len(commands))
+		}
+	}
+}
+
+// BenchmarkGenerate renders and writes every file, as a forced regeneration does
+func BenchmarkGenerate(b *testing.B) {
+	fsys := benchmarkTree()
+	generator := NewGenerator(benchmarkConfig(b))
+	generator.SetForceRegeneration(true)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := generator.Generate(context.Background(), fsys); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGenerate_Unchanged measures the common pre-commit case: nothing changed since the last run
+func BenchmarkGenerate_Unchanged(b *testing.B) {
+	fsys := benchmarkTree()
+	generator := NewGenerator(benchmarkConfig(b))
+	if err := generator.Generate(context.Background(), fsys); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := generator.Generate(context.Background(), fsys); err != nil {
+			b.Fatal(err)
+		}
+		if skipped := generator.GetStats()["skipped_files"]; skipped != 5050 {
+			b.Fatalf("skipped %d files, want 5050", skipped)
+		}
+	}
+}
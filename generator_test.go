@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -308,6 +309,404 @@ Test command with quotes in descriptions.`
 	}
 }
 
+func TestGenerator_ValidateMethod(t *testing.T) {
+	// Create temporary directory for test output
+	tempDir, err := os.MkdirTemp("", "adder-validate-method-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	inputDir := filepath.Join(tempDir, "input")
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input dir: %v", err)
+	}
+
+	// Write test markdown file with required fields, enums and flag rules
+	testMarkdown := `---
+title: Export Command
+command:
+  name: export [target]
+  arguments:
+    - name: target
+      required: true
+      type: string
+  flags:
+    - name: format
+      type: string
+      default: json
+      enum:
+        - json
+        - yaml
+    - name: token
+      type: string
+      required: true
+      required_together:
+        - user
+    - name: user
+      type: string
+    - name: stdout
+      type: bool
+      mutually_exclusive:
+        - file
+    - name: file
+      type: string
+---`
+
+	if err := os.WriteFile(filepath.Join(inputDir, "export.md"), []byte(testMarkdown), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	outputDir := filepath.Join(tempDir, "output")
+	config := &Config{
+		InputDir:            inputDir,
+		OutputDir:           outputDir,
+		Package:             "testpkg",
+		GeneratedFileSuffix: "_generated.go",
+	}
+
+	if err := New(config).Generate(); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "export_generated.go"))
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	contentStr := string(content)
+	expectedStrings := []string{
+		"var _ adder.Validator = (*ExportRequest)(nil)",
+		"var ExportRequestValidateFunc func(req *ExportRequest) error",
+		"func (r *ExportRequest) Validate() error",
+		`adder.ValidateRequired("target", r.Arguments.Target)`,
+		`adder.ValidateRequired("token", r.Flags.Token)`,
+		`adder.ValidateEnum("format", r.Flags.Format, []string{"json", "yaml"})`,
+		`adder.ValidateRequiredTogether(adder.FieldSet{Name: "token", Set: r.Flags.Token != ""}, adder.FieldSet{Name: "user", Set: r.Flags.User != ""})`,
+		`adder.ValidateMutuallyExclusive(adder.FieldSet{Name: "stdout", Set: r.Flags.Stdout != false}, adder.FieldSet{Name: "file", Set: r.Flags.File != ""})`,
+		`cmd.MarkFlagsRequiredTogether("token", "user")`,
+		`cmd.MarkFlagsMutuallyExclusive("stdout", "file")`,
+		`adder.CheckRequiredFlags(cmd, adder.RequiredFlag{Name: "token", Key: "flags.token"})`,
+		"if err := req.Validate(); err != nil",
+	}
+
+	for _, expected := range expectedStrings {
+		if !contains(contentStr, expected) {
+			t.Errorf("Generated content missing expected string: %q", expected)
+		}
+	}
+
+	// Required flags are checked after --from-file is merged instead of by cobra
+	if contains(contentStr, `cmd.MarkFlagRequired("token")`) {
+		t.Error("required flag should not be marked required with cobra")
+	}
+}
+
+func TestGenerator_OutputTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name: "single result",
+			output: `  output:
+    format: json
+    fields:
+      - name: name
+        description: User name
+      - name: admin
+        type: bool`,
+			expected: []string{
+				"type ShowResponse struct",
+				"Name string `json:\"name\"` // User name",
+				"Admin bool `json:\"admin\"`",
+				"type ShowHandler func(cmd *cobra.Command, req *ShowRequest) (*ShowResponse, error)",
+				`adder.AddOutputFlag(cmd, "json")`,
+				`adder.CommandPrinter(cmd, adder.Column{Field: "name", Header: "NAME"}, adder.Column{Field: "admin", Header: "ADMIN"})`,
+				"return printer.Print(cmd.OutOrStdout(), result)",
+			},
+		},
+		{
+			name: "list result with columns",
+			output: `  output:
+    list: true
+    columns: [created-at]
+    fields:
+      - name: name
+      - name: created-at`,
+			expected: []string{
+				"type ShowHandler func(cmd *cobra.Command, req *ShowRequest) ([]ShowResponse, error)",
+				`adder.AddOutputFlag(cmd, "table")`,
+				`adder.CommandPrinter(cmd, adder.Column{Field: "createdAt", Header: "CREATED AT"})`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser(DefaultConfig())
+			cmd, err := parser.ParseContent("---\ntitle: Show user\ncommand:\n  name: show\n"+tt.output+"\n---", "show.md")
+			if err != nil {
+				t.Fatalf("ParseContent() failed: %v", err)
+			}
+
+			generator := NewGenerator(DefaultConfig())
+			content, err := generator.generateCommand(cmd)
+			if err != nil {
+				t.Fatalf("generateCommand() failed: %v", err)
+			}
+
+			for _, expected := range tt.expected {
+				if !contains(content, expected) {
+					t.Errorf("Generated content missing expected string: %q", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_DeclaredErrors(t *testing.T) {
+	content := `---
+title: Delete profile
+command:
+  name: delete
+  errors:
+    - name: not-found
+      code: 4
+      description: The profile does not exist
+    - name: in-use
+      code: 5
+---`
+
+	parser := NewParser(DefaultConfig())
+	cmd, err := parser.ParseContent(content, "delete.md")
+	if err != nil {
+		t.Fatalf("ParseContent() failed: %v", err)
+	}
+
+	generator := NewGenerator(DefaultConfig())
+	generated, err := generator.generateCommand(cmd)
+	if err != nil {
+		t.Fatalf("generateCommand() failed: %v", err)
+	}
+
+	expected := []string{
+		"const DeleteNotFoundExitCode = 4",
+		"// The profile does not exist",
+		"func NewDeleteNotFoundError(format string, args ...interface{}) error {",
+		"return adder.Errorf(DeleteNotFoundExitCode, format, args...)",
+		"func NewDeleteInUseError(format string, args ...interface{}) error {",
+		`Long:    "Delete profile\n\nExit Codes:\n  0   success\n`,
+		`  4   not-found: The profile does not exist\n  5   in-use"`,
+	}
+
+	for _, want := range expected {
+		if !contains(generated, want) {
+			t.Errorf("Generated content missing expected string: %q", want)
+		}
+	}
+}
+
+func TestGenerator_SetupHook(t *testing.T) {
+	content := `---
+title: Profile commands
+command:
+  name: profile
+  setup: true
+  persistent_flags:
+    - name: config
+      type: string
+---`
+
+	parser := NewParser(DefaultConfig())
+	cmd, err := parser.ParseContent(content, "profile.md")
+	if err != nil {
+		t.Fatalf("ParseContent() failed: %v", err)
+	}
+
+	generator := NewGenerator(DefaultConfig())
+	generated, err := generator.generateCommand(cmd)
+	if err != nil {
+		t.Fatalf("generateCommand() failed: %v", err)
+	}
+
+	expected := []string{
+		"type ProfileSetup func(cmd *cobra.Command, req *ProfileRequest) (teardown func() error, err error)",
+		"func NewProfileCommand(handler ProfileHandler, setup ProfileSetup, opts ...adder.CommandOption) *cobra.Command {",
+		"adder.RegisterSetup(cmd, func(cmd *cobra.Command) (func() error, error) {",
+		`req.PersistentFlags.Config, _ = cmd.Flags().GetString("config")`,
+	}
+
+	for _, want := range expected {
+		if !contains(generated, want) {
+			t.Errorf("Generated content missing expected string: %q", want)
+		}
+	}
+}
+
+func TestGenerator_Prompts(t *testing.T) {
+	content := `---
+title: Create project
+command:
+  name: create
+  arguments:
+    - name: name
+      required: true
+      prompt: Project name
+  flags:
+    - name: template
+      enum: [go, node]
+      required: true
+      prompt: true
+    - name: token
+      description: API token
+      sensitive: true
+      prompt: true
+    - name: private
+      type: bool
+      required: true
+      prompt: Make the project private?
+---`
+
+	parser := NewParser(DefaultConfig())
+	cmd, err := parser.ParseContent(content, "create.md")
+	if err != nil {
+		t.Fatalf("ParseContent() failed: %v", err)
+	}
+
+	generator := NewGenerator(DefaultConfig())
+	generated, err := generator.generateCommand(cmd)
+	if err != nil {
+		t.Fatalf("generateCommand() failed: %v", err)
+	}
+
+	expected := []string{
+		"Args: options.ExactArgsOrPrompt(1, 0),",
+		"prompter := options.PrompterFor(cmd)",
+		"if prompter != nil {",
+		`if req.Arguments.Name == "" {`,
+		`prompter.String("Project name")`,
+		`if req.Flags.Template == "" && !cmd.Flags().Changed("template") {`,
+		`prompter.Select("template", []string{"go", "node"})`,
+		`prompter.Password("API token")`,
+		`if req.Flags.Private == false && !cmd.Flags().Changed("private") {`,
+		`prompter.Confirm("Make the project private?", false)`,
+		`} else if err := adder.CheckRequiredFlags(cmd, adder.RequiredFlag{Name: "template", Key: "flags.template"}, adder.RequiredFlag{Name: "private", Key: "flags.private"}); err != nil {`,
+	}
+	for _, want := range expected {
+		if !contains(generated, want) {
+			t.Errorf("Generated content missing expected string: %q", want)
+		}
+	}
+
+	// Prompted flags are checked by Validate instead of cobra, so they can be answered interactively
+	if contains(generated, `cmd.MarkFlagRequired("template")`) {
+		t.Error("prompted required flag should not be marked required with cobra")
+	}
+}
+
+func TestGenerator_Confirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		expected []string
+	}{
+		{
+			name:    "confirm message",
+			command: "  name: delete [name]\n  arguments:\n    - name: name\n  confirm: \"Delete {{.Arguments.Name}}?\"",
+			expected: []string{
+				"adder.AddYesFlag(cmd)",
+				"prompter := options.PrompterFor(cmd)",
+				`if err := adder.Confirm(cmd, prompter, "Delete {{.Arguments.Name}}?", req); err != nil {`,
+			},
+		},
+		{
+			name:    "destructive without message",
+			command: "  name: delete\n  destructive: true",
+			expected: []string{
+				"adder.AddYesFlag(cmd)",
+				`if err := adder.Confirm(cmd, prompter, "", req); err != nil {`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser(DefaultConfig())
+			cmd, err := parser.ParseContent("---\ntitle: Delete\ncommand:\n"+tt.command+"\n---", "delete.md")
+			if err != nil {
+				t.Fatalf("ParseContent() failed: %v", err)
+			}
+
+			generator := NewGenerator(DefaultConfig())
+			generated, err := generator.generateCommand(cmd)
+			if err != nil {
+				t.Fatalf("generateCommand() failed: %v", err)
+			}
+
+			for _, want := range tt.expected {
+				if !contains(generated, want) {
+					t.Errorf("Generated content missing expected string: %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_RequestBuilder(t *testing.T) {
+	content := `---
+title: Say hello
+command:
+  name: greet [name]
+  arguments:
+    - name: name
+  flags:
+    - name: repeat
+      type: int
+      default: 1
+    - name: capitalize
+      type: bool
+  persistent_flags:
+    - name: config
+      default: ~/.hello.yaml
+---`
+
+	parser := NewParser(DefaultConfig())
+	cmd, err := parser.ParseContent(content, "greet.md")
+	if err != nil {
+		t.Fatalf("ParseContent() failed: %v", err)
+	}
+
+	generator := NewGenerator(DefaultConfig())
+	generated, err := generator.generateCommand(cmd)
+	if err != nil {
+		t.Fatalf("generateCommand() failed: %v", err)
+	}
+
+	expected := []string{
+		"func DefaultGreetRequest() *GreetRequest {",
+		"Repeat: 1,",
+		`Config: "~/.hello.yaml",`,
+		"func NewGreetRequestBuilder() *GreetRequestBuilder {",
+		"func (b *GreetRequestBuilder) WithName(value string) *GreetRequestBuilder {",
+		"func (b *GreetRequestBuilder) WithRepeat(value int) *GreetRequestBuilder {",
+		"func (b *GreetRequestBuilder) WithCapitalize(value bool) *GreetRequestBuilder {",
+		"b.req.PersistentFlags.Config = value",
+		"func (b *GreetRequestBuilder) Build() *GreetRequest {",
+	}
+	for _, want := range expected {
+		if !contains(generated, want) {
+			t.Errorf("Generated content missing expected string: %q", want)
+		}
+	}
+
+	// Flags without a declared default keep the zero value
+	if contains(generated, "Capitalize: false,") {
+		t.Error("flags without a default should not be set in the default request")
+	}
+}
+
 func TestGenerator_GetStats(t *testing.T) {
 	generator := &Generator{
 		commands: []*Command{
@@ -463,3 +862,57 @@ func contains(s, substr string) bool {
 		(len(substr) <= len(s) && s[:len(substr)] == substr) ||
 		contains(s[1:], substr))
 }
+
+func TestAdder_Changes(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	for name, title := range map[string]string{"alpha.md": "Alpha", "beta.md": "Beta"} {
+		content := "---\ntitle: " + title + "\ncommand:\n  name: " + strings.TrimSuffix(name, ".md") + "\n---\n"
+		if err := os.WriteFile(filepath.Join(inputDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := DefaultConfig()
+	config.InputDir = inputDir
+	config.OutputDir = outputDir
+	config.Package = "testpkg"
+
+	changes, err := New(config).Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 2 || changes[0].Action != ChangeCreate || changes[1].Action != ChangeCreate {
+		t.Fatalf("Changes() before generating = %+v, want two creates", changes)
+	}
+	if _, err := os.Stat(changes[0].Path); !os.IsNotExist(err) {
+		t.Errorf("Changes() must not write %s", changes[0].Path)
+	}
+
+	if err := New(config).GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	changes, err = New(config).Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("Changes() after generating = %+v, want none", changes)
+	}
+
+	// Editing a generated file is reported as an update
+	edited := filepath.Join(outputDir, "beta_generated.go")
+	if err := os.WriteFile(edited, []byte("package testpkg\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit generated file: %v", err)
+	}
+	changes, err = New(config).Changes(context.Background())
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != edited || changes[0].Action != ChangeUpdate {
+		t.Fatalf("Changes() after editing = %+v, want an update of %s", changes, edited)
+	}
+	if !strings.HasPrefix(changes[0].Diff(), "--- a/"+edited) {
+		t.Errorf("Diff() = %q", changes[0].Diff())
+	}
+}
@@ -0,0 +1,1000 @@
+package adder
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Importer converts existing cobra command trees into adder markdown
+// The Go source is analyzed statically; it is never compiled or run
+type Importer struct {
+	config *Config
+}
+
+// NewImporter creates a new importer writing markdown in the layout of config
+func NewImporter(config *Config) *Importer {
+	return &Importer{config: config}
+}
+
+// ImportedCommand is a cobra command found in Go source
+type ImportedCommand struct {
+	Command
+	Example     string             // Example text of the cobra command
+	Source      string             // Position of the cobra.Command literal
+	Subcommands []*ImportedCommand // Commands added with AddCommand
+
+	parent *ImportedCommand
+}
+
+// ImportResult is the command tree found by Import
+type ImportResult struct {
+	Root     *ImportedCommand
+	Warnings []string // Parts of the source that could not be imported
+}
+
+// Count returns the number of imported commands, including the root
+func (r *ImportResult) Count() int {
+	var count func(cmd *ImportedCommand) int
+	count = func(cmd *ImportedCommand) int {
+		n := 1
+		for _, sub := range cmd.Subcommands {
+			n += count(sub)
+		}
+		return n
+	}
+	return count(r.Root)
+}
+
+// flagTypes maps pflag registration methods to adder flag types
+var flagTypes = map[string]string{
+	"String":      TypeString,
+	"Bool":        TypeBool,
+	"Int":         TypeInt,
+	"Int8":        TypeInt,
+	"Int16":       TypeInt,
+	"Int32":       TypeInt,
+	"Int64":       TypeInt,
+	"Uint":        TypeInt,
+	"Uint8":       TypeInt,
+	"Uint16":      TypeInt,
+	"Uint32":      TypeInt,
+	"Uint64":      TypeInt,
+	"StringSlice": TypeStringArray,
+	"StringArray": TypeStringArray,
+}
+
+// unsupportedFlagTypes are pflag registration methods without an adder flag type
+var unsupportedFlagTypes = map[string]bool{
+	"Count": true, "Duration": true, "DurationSlice": true, "Float32": true, "Float64": true,
+	"Float32Slice": true, "Float64Slice": true, "IntSlice": true, "Int32Slice": true,
+	"Int64Slice": true, "UintSlice": true, "BoolSlice": true, "IP": true, "IPSlice": true,
+	"IPMask": true, "IPNet": true, "StringToString": true, "StringToInt": true,
+	"StringToInt64": true, "BytesHex": true, "BytesBase64": true,
+}
+
+// importAnalysis holds the state of a single Import
+type importAnalysis struct {
+	fset     *token.FileSet
+	commands []*ImportedCommand // in source order
+	literals map[*ast.CompositeLit]*ImportedCommand
+	vars     map[string]*ImportedCommand // keyed by scope and name
+	funcs    map[string]*ImportedCommand // keyed by package and function name
+	flagSets map[string]importFlagSet    // variables holding a command's flag set
+	marks    []importMark                // applied once all flags are registered
+	warnings []string
+}
+
+// importFlagSet is the flag set of a command, as returned by Flags() or PersistentFlags()
+type importFlagSet struct {
+	cmd        *ImportedCommand
+	persistent bool
+}
+
+// importMark is a MarkFlag* call on a command
+type importMark struct {
+	cmd    *ImportedCommand
+	method string
+	names  []string
+	pos    token.Pos
+}
+
+// importFile is a parsed Go file and the scope its package level names live in
+type importFile struct {
+	file  *ast.File
+	pkg   string // package directory, used as the package scope
+	cobra string // name the cobra package is imported as
+}
+
+// Import analyzes the Go source in dir and its subdirectories for cobra commands
+// Test files, testdata, vendor and hidden directories are skipped
+func (i *Importer) Import(dir string) (*ImportResult, error) {
+	a := &importAnalysis{
+		fset:     token.NewFileSet(),
+		literals: make(map[*ast.CompositeLit]*ImportedCommand),
+		vars:     make(map[string]*ImportedCommand),
+		funcs:    make(map[string]*ImportedCommand),
+		flagSets: make(map[string]importFlagSet),
+	}
+
+	files, err := a.parseFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		a.collectCommands(f)
+	}
+	if len(a.commands) == 0 {
+		return nil, fmt.Errorf("no cobra.Command literals found in %s", dir)
+	}
+	for _, f := range files {
+		a.collectRegistrations(f)
+	}
+	a.applyMarks()
+
+	root := a.root(i.config.BinaryName)
+	return &ImportResult{Root: root, Warnings: a.warnings}, nil
+}
+
+// parseFiles parses the non-test Go files below dir
+func (a *importAnalysis) parseFiles(dir string) ([]importFile, error) {
+	var files []importFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if p != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(a.fset, p, nil, 0)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", p, err)
+		}
+
+		cobraName := ""
+		for _, imp := range file.Imports {
+			if imp.Path.Value != `"github.com/spf13/cobra"` {
+				continue
+			}
+			cobraName = "cobra"
+			if imp.Name != nil {
+				cobraName = imp.Name.Name
+			}
+		}
+		files = append(files, importFile{file: file, pkg: filepath.Dir(p), cobra: cobraName})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// collectCommands finds the cobra.Command literals of a file and the names they are bound to
+func (a *importAnalysis) collectCommands(f importFile) {
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || f.cobra == "" || !isSelector(lit.Type, f.cobra, "Command") {
+			return true
+		}
+		if cmd := a.newCommand(lit); cmd != nil {
+			a.literals[lit] = cmd
+			a.commands = append(a.commands, cmd)
+		}
+		return true
+	})
+
+	for _, decl := range f.file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			a.bindValueSpecs(decl, f.pkg, f.pkg)
+		case *ast.FuncDecl:
+			if decl.Body == nil {
+				continue
+			}
+			scope := funcScope(f.pkg, decl)
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					a.bindAssignment(n, scope, f.pkg)
+				case *ast.GenDecl:
+					a.bindValueSpecs(n, scope, f.pkg)
+				case *ast.ReturnStmt:
+					if len(n.Results) > 0 && a.funcs[scope] == nil {
+						if cmd := a.resolveCommand(n.Results[0], scope, f.pkg); cmd != nil {
+							a.funcs[scope] = cmd
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// collectRegistrations finds AddCommand, flag registration and MarkFlag* calls of a file
+func (a *importAnalysis) collectRegistrations(f importFile) {
+	for _, decl := range f.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			if gen, ok := decl.(*ast.GenDecl); ok {
+				a.bindValueSpecs(gen, f.pkg, f.pkg)
+			}
+			continue
+		}
+
+		scope := funcScope(f.pkg, fn)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				a.bindAssignment(n, scope, f.pkg)
+			case *ast.GenDecl:
+				a.bindValueSpecs(n, scope, f.pkg)
+			case *ast.CallExpr:
+				a.registerCall(n, scope, f)
+			}
+			return true
+		})
+	}
+}
+
+// bindValueSpecs binds the names of var declarations to commands and flag sets
+func (a *importAnalysis) bindValueSpecs(decl *ast.GenDecl, scope, pkg string) {
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Names) != len(vs.Values) {
+			continue
+		}
+		for j, name := range vs.Names {
+			a.bind(name.Name, vs.Values[j], scope, pkg)
+		}
+	}
+}
+
+// bindAssignment binds the names of an assignment to commands and flag sets
+func (a *importAnalysis) bindAssignment(stmt *ast.AssignStmt, scope, pkg string) {
+	if len(stmt.Lhs) != len(stmt.Rhs) {
+		return
+	}
+	for j, lhs := range stmt.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok {
+			a.bind(ident.Name, stmt.Rhs[j], scope, pkg)
+		}
+	}
+}
+
+// bind records that name refers to the command or flag set value evaluates to
+func (a *importAnalysis) bind(name string, value ast.Expr, scope, pkg string) {
+	if name == "_" {
+		return
+	}
+	if flagSet, ok := a.resolveFlagSet(value, scope, pkg); ok {
+		a.flagSets[scope+"."+name] = flagSet
+		return
+	}
+	if cmd := a.resolveCommand(value, scope, pkg); cmd != nil {
+		a.vars[scope+"."+name] = cmd
+	}
+}
+
+// registerCall applies a call that adds subcommands, registers flags or marks flags
+func (a *importAnalysis) registerCall(call *ast.CallExpr, scope string, f importFile) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	method := sel.Sel.Name
+
+	// cobra.MarkFlagRequired(cmd.Flags(), "name")
+	if isIdent(sel.X, f.cobra) {
+		if method == "MarkFlagRequired" && len(call.Args) == 2 {
+			if flagSet, ok := a.resolveFlagSet(call.Args[0], scope, f.pkg); ok {
+				a.mark(flagSet.cmd, method, call.Args[1:], call.Pos())
+			}
+		}
+		return
+	}
+
+	if flagSet, ok := a.resolveFlagSet(sel.X, scope, f.pkg); ok {
+		a.registerFlag(flagSet, method, call)
+		return
+	}
+
+	cmd := a.resolveCommand(sel.X, scope, f.pkg)
+	if cmd == nil {
+		return
+	}
+	switch method {
+	case "AddCommand":
+		for _, arg := range call.Args {
+			child := a.resolveCommand(arg, scope, f.pkg)
+			if child == nil {
+				a.warn(arg.Pos(), "%s: could not resolve a command added with AddCommand", cmd.Name)
+				continue
+			}
+			a.addSubcommand(cmd, child, arg.Pos())
+		}
+	case "MarkFlagRequired", "MarkPersistentFlagRequired",
+		"MarkFlagsMutuallyExclusive", "MarkFlagsRequiredTogether", "MarkFlagsOneRequired":
+		a.mark(cmd, method, call.Args, call.Pos())
+	}
+}
+
+// addSubcommand makes child a subcommand of parent
+func (a *importAnalysis) addSubcommand(parent, child *ImportedCommand, pos token.Pos) {
+	if child.parent != nil {
+		a.warn(pos, "%s: already added to %s, ignoring AddCommand on %s", child.Name, child.parent.Name, parent.Name)
+		return
+	}
+	for p := parent; p != nil; p = p.parent {
+		if p == child {
+			a.warn(pos, "%s: adding it to %s would create a cycle", child.Name, parent.Name)
+			return
+		}
+	}
+	child.parent = parent
+	parent.Subcommands = append(parent.Subcommands, child)
+}
+
+// registerFlag imports a flag registered with a pflag method such as StringVarP
+func (a *importAnalysis) registerFlag(flagSet importFlagSet, method string, call *ast.CallExpr) {
+	base, isVar, hasShorthand := method, false, false
+	if strings.HasSuffix(base, "P") {
+		base, hasShorthand = strings.TrimSuffix(base, "P"), true
+	}
+	if strings.HasSuffix(base, "Var") {
+		base, isVar = strings.TrimSuffix(base, "Var"), true
+	}
+
+	flagType, supported := flagTypes[base]
+	if !supported && !unsupportedFlagTypes[base] {
+		return // not a flag registration, e.g. MarkHidden or SortFlags
+	}
+
+	idx := 0
+	if isVar {
+		idx++
+	}
+	if idx >= len(call.Args) {
+		return
+	}
+	name, ok := stringValue(call.Args[idx])
+	if !ok {
+		a.warn(call.Pos(), "%s: skipping flag with a name that is not a string literal", flagSet.cmd.Name)
+		return
+	}
+	if !supported {
+		a.warn(call.Pos(), "%s: skipping flag --%s of unsupported type %s", flagSet.cmd.Name, name, base)
+		return
+	}
+
+	flag := Flag{Name: name, Type: flagType}
+	idx++
+	if hasShorthand && idx < len(call.Args) {
+		flag.Shorthand, _ = stringValue(call.Args[idx])
+		idx++
+	}
+	if idx+1 >= len(call.Args) {
+		a.warn(call.Pos(), "%s: skipping flag --%s with unexpected arguments", flagSet.cmd.Name, name)
+		return
+	}
+
+	value, ok := literalValue(call.Args[idx], flagType)
+	if !ok {
+		a.warn(call.Args[idx].Pos(), "%s: default of flag --%s is not a literal, left unset", flagSet.cmd.Name, name)
+	}
+	flag.Default = value
+	flag.Description, _ = stringValue(call.Args[idx+1])
+
+	if flagSet.persistent {
+		flagSet.cmd.PersistentFlags = append(flagSet.cmd.PersistentFlags, flag)
+	} else {
+		flagSet.cmd.Flags = append(flagSet.cmd.Flags, flag)
+	}
+}
+
+// mark records a MarkFlag* call to apply once all flags are registered
+func (a *importAnalysis) mark(cmd *ImportedCommand, method string, args []ast.Expr, pos token.Pos) {
+	var names []string
+	for _, arg := range args {
+		if name, ok := stringValue(arg); ok {
+			names = append(names, name)
+		}
+	}
+	a.marks = append(a.marks, importMark{cmd: cmd, method: method, names: names, pos: pos})
+}
+
+// applyMarks applies required flags and flag groups
+func (a *importAnalysis) applyMarks() {
+	for _, m := range a.marks {
+		if len(m.names) == 0 {
+			continue
+		}
+		flags := make([]*Flag, 0, len(m.names))
+		for _, name := range m.names {
+			flag := findImportedFlag(m.cmd, name)
+			if flag == nil {
+				a.warn(m.pos, "%s: %s refers to unknown flag --%s", m.cmd.Name, m.method, name)
+				flags = nil
+				break
+			}
+			flags = append(flags, flag)
+		}
+		if flags == nil {
+			continue
+		}
+
+		others := m.names[1:]
+		switch m.method {
+		case "MarkFlagRequired", "MarkPersistentFlagRequired":
+			flags[0].Required = true
+		case "MarkFlagsMutuallyExclusive":
+			flags[0].MutuallyExclusive = append(flags[0].MutuallyExclusive, others...)
+		case "MarkFlagsRequiredTogether":
+			flags[0].RequiredTogether = append(flags[0].RequiredTogether, others...)
+		case "MarkFlagsOneRequired":
+			flags[0].OneRequired = append(flags[0].OneRequired, others...)
+		}
+	}
+}
+
+// findImportedFlag returns the flag of cmd with the given name
+func findImportedFlag(cmd *ImportedCommand, name string) *Flag {
+	for i := range cmd.Flags {
+		if cmd.Flags[i].Name == name {
+			return &cmd.Flags[i]
+		}
+	}
+	for i := range cmd.PersistentFlags {
+		if cmd.PersistentFlags[i].Name == name {
+			return &cmd.PersistentFlags[i]
+		}
+	}
+	return nil
+}
+
+// root picks the root of the command tree and attaches commands without a parent to it
+// The root is the command named binaryName, or the command with the most descendants
+func (a *importAnalysis) root(binaryName string) *ImportedCommand {
+	var roots []*ImportedCommand
+	for _, cmd := range a.commands {
+		if cmd.parent == nil {
+			roots = append(roots, cmd)
+		}
+	}
+
+	root := roots[0]
+	for _, cmd := range roots[1:] {
+		if binaryName != "" && (root.Name == binaryName) != (cmd.Name == binaryName) {
+			if cmd.Name == binaryName {
+				root = cmd
+			}
+			continue
+		}
+		if descendants(cmd) > descendants(root) {
+			root = cmd
+		}
+	}
+
+	for _, cmd := range roots {
+		if cmd == root {
+			continue
+		}
+		a.warnf("%s: no AddCommand call found (%s), imported as a subcommand of %s", cmd.Name, cmd.Source, root.Name)
+		cmd.parent = root
+		root.Subcommands = append(root.Subcommands, cmd)
+	}
+	return root
+}
+
+// descendants counts the subcommands below cmd
+func descendants(cmd *ImportedCommand) int {
+	n := 0
+	for _, sub := range cmd.Subcommands {
+		n += 1 + descendants(sub)
+	}
+	return n
+}
+
+// newCommand imports the fields of a cobra.Command literal
+func (a *importAnalysis) newCommand(lit *ast.CompositeLit) *ImportedCommand {
+	cmd := &ImportedCommand{Source: a.fset.Position(lit.Pos()).String()}
+
+	var use string
+	var requiredArgs int
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Use":
+			use, _ = stringValue(kv.Value)
+		case "Short":
+			if cmd.Title, ok = stringValue(kv.Value); !ok {
+				a.warn(kv.Value.Pos(), "Short is not a string literal, left empty")
+			}
+		case "Long":
+			if cmd.Description, ok = stringValue(kv.Value); !ok {
+				a.warn(kv.Value.Pos(), "Long is not a string literal, left empty")
+			}
+		case "Example":
+			cmd.Example, _ = stringValue(kv.Value)
+		case "Aliases":
+			cmd.Aliases = stringList(kv.Value)
+		case "Hidden":
+			cmd.Hidden = isIdent(kv.Value, "true")
+		case "Args":
+			requiredArgs = minimumArgs(kv.Value)
+		}
+	}
+
+	fields := strings.Fields(use)
+	if len(fields) == 0 {
+		a.warn(lit.Pos(), "skipping cobra.Command without a Use")
+		return nil
+	}
+	cmd.Name = fields[0]
+	cmd.Arguments = parseUseArguments(fields[1:])
+
+	for j := range cmd.Arguments {
+		if j < requiredArgs {
+			cmd.Arguments[j].Required = true
+		}
+	}
+	return cmd
+}
+
+// parseUseArguments imports the positional arguments named in a Use line
+// <name> and bare names are required, [name] is optional
+func parseUseArguments(fields []string) []Argument {
+	var args []Argument
+	for _, field := range fields {
+		required := !strings.HasPrefix(field, "[")
+		name := strings.TrimSuffix(strings.Trim(field, "[]<>"), "...")
+		name = strings.Trim(name, "[]<>")
+		if name == "" || name == "flags" || name == "command" || strings.ContainsAny(name, "|=") {
+			continue
+		}
+		args = append(args, Argument{Name: name, Required: required, Type: TypeString})
+	}
+	return args
+}
+
+// minimumArgs returns the number of required arguments of a cobra.PositionalArgs expression
+func minimumArgs(expr ast.Expr) int {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return 0
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return 0
+	}
+	switch sel.Sel.Name {
+	case "ExactArgs", "MinimumNArgs", "RangeArgs":
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.INT {
+			n, _ := strconv.Atoi(lit.Value)
+			return n
+		}
+	}
+	return 0
+}
+
+// resolveCommand returns the command an expression evaluates to, if known
+func (a *importAnalysis) resolveCommand(expr ast.Expr, scope, pkg string) *ImportedCommand {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return a.resolveCommand(e.X, scope, pkg)
+		}
+	case *ast.ParenExpr:
+		return a.resolveCommand(e.X, scope, pkg)
+	case *ast.CompositeLit:
+		return a.literals[e]
+	case *ast.Ident:
+		if cmd := a.vars[scope+"."+e.Name]; cmd != nil {
+			return cmd
+		}
+		return a.vars[pkg+"."+e.Name]
+	case *ast.SelectorExpr:
+		// A package level variable of another package, e.g. serve.Cmd
+		return lookupUnique(a.vars, func(key string) bool {
+			return !strings.Contains(key, "#") && strings.HasSuffix(key, "."+e.Sel.Name)
+		})
+	case *ast.CallExpr:
+		name := ""
+		switch fn := e.Fun.(type) {
+		case *ast.Ident:
+			if cmd := a.funcs[pkg+"#"+fn.Name]; cmd != nil {
+				return cmd
+			}
+			name = fn.Name
+		case *ast.SelectorExpr:
+			// A function of another package or a method, e.g. serve.NewCommand()
+			name = fn.Sel.Name
+		default:
+			return nil
+		}
+		return lookupUnique(a.funcs, func(key string) bool {
+			fn := key[strings.LastIndex(key, "#")+1:]
+			return fn == name || strings.HasSuffix(fn, "."+name)
+		})
+	}
+	return nil
+}
+
+// resolveFlagSet returns the flag set an expression evaluates to, if known
+func (a *importAnalysis) resolveFlagSet(expr ast.Expr, scope, pkg string) (importFlagSet, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if flagSet, ok := a.flagSets[scope+"."+e.Name]; ok {
+			return flagSet, true
+		}
+		flagSet, ok := a.flagSets[pkg+"."+e.Name]
+		return flagSet, ok
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || len(e.Args) != 0 {
+			return importFlagSet{}, false
+		}
+		if sel.Sel.Name != "Flags" && sel.Sel.Name != "PersistentFlags" {
+			return importFlagSet{}, false
+		}
+		cmd := a.resolveCommand(sel.X, scope, pkg)
+		if cmd == nil {
+			return importFlagSet{}, false
+		}
+		return importFlagSet{cmd: cmd, persistent: sel.Sel.Name == "PersistentFlags"}, true
+	}
+	return importFlagSet{}, false
+}
+
+// lookupUnique returns the command of the only binding whose key matches, or nil if it is ambiguous
+func lookupUnique(bindings map[string]*ImportedCommand, match func(key string) bool) *ImportedCommand {
+	var found *ImportedCommand
+	for key, cmd := range bindings {
+		if !match(key) {
+			continue
+		}
+		if found != nil && found != cmd {
+			return nil
+		}
+		found = cmd
+	}
+	return found
+}
+
+// warn records a warning at a source position
+func (a *importAnalysis) warn(pos token.Pos, format string, args ...interface{}) {
+	a.warnf("%s: %s", a.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+// warnf records a warning
+func (a *importAnalysis) warnf(format string, args ...interface{}) {
+	a.warnings = append(a.warnings, fmt.Sprintf(format, args...))
+}
+
+// funcScope returns the scope of the local names of a function
+// The # separates it from package scopes, whose names are looked up across packages
+func funcScope(pkg string, fn *ast.FuncDecl) string {
+	name := fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			name = ident.Name + "." + name
+		}
+	}
+	return pkg + "#" + name
+}
+
+// isSelector reports whether expr is pkg.name
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == name && isIdent(sel.X, pkg)
+}
+
+// isIdent reports whether expr is the identifier name
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// stringValue returns the value of a string literal or a concatenation of them
+func stringValue(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringValue(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringValue(e.Y)
+		return left + right, ok
+	case *ast.ParenExpr:
+		return stringValue(e.X)
+	}
+	return "", false
+}
+
+// stringList returns the string literals of a []string literal
+func stringList(expr ast.Expr) []string {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, elt := range lit.Elts {
+		if value, ok := stringValue(elt); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// literalValue returns the default value of a flag, or nil for zero values
+func literalValue(expr ast.Expr, flagType string) (interface{}, bool) {
+	switch flagType {
+	case TypeString:
+		value, ok := stringValue(expr)
+		if !ok || value == "" {
+			return nil, ok
+		}
+		return value, true
+	case TypeBool:
+		if isIdent(expr, "true") {
+			return true, true
+		}
+		return nil, isIdent(expr, "false")
+	case TypeInt:
+		sign := 1
+		if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+			sign, expr = -1, unary.X
+		}
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, false
+		}
+		n, err := strconv.Atoi(lit.Value)
+		if err != nil {
+			return nil, false
+		}
+		if n == 0 {
+			return nil, true
+		}
+		return sign * n, true
+	case TypeStringArray:
+		if isIdent(expr, NilValue) {
+			return nil, true
+		}
+		lit, ok := expr.(*ast.CompositeLit)
+		if !ok {
+			return nil, false
+		}
+		values := stringList(lit)
+		if len(values) != len(lit.Elts) {
+			return nil, false
+		}
+		if len(values) == 0 {
+			return nil, true
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// importFrontmatter is the frontmatter written for an imported command
+type importFrontmatter struct {
+	Title   string        `yaml:"title,omitempty"`
+	Command importCommand `yaml:"command"`
+}
+
+type importCommand struct {
+	Name            string           `yaml:"name"`
+	Aliases         []string         `yaml:"aliases,omitempty"`
+	Hidden          bool             `yaml:"hidden,omitempty"`
+	Arguments       []importArgument `yaml:"arguments,omitempty"`
+	Flags           []importFlag     `yaml:"flags,omitempty"`
+	PersistentFlags []importFlag     `yaml:"persistent_flags,omitempty"`
+}
+
+type importArgument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	Type        string `yaml:"type"`
+}
+
+type importFlag struct {
+	Name              string      `yaml:"name"`
+	Shorthand         string      `yaml:"shorthand,omitempty"`
+	Description       string      `yaml:"description,omitempty"`
+	Type              string      `yaml:"type"`
+	Default           interface{} `yaml:"default,omitempty"`
+	Required          bool        `yaml:"required,omitempty"`
+	MutuallyExclusive []string    `yaml:"mutually_exclusive,omitempty"`
+	RequiredTogether  []string    `yaml:"required_together,omitempty"`
+	OneRequired       []string    `yaml:"one_required,omitempty"`
+}
+
+// Files renders the markdown of an import, keyed by slash separated path relative to the input directory
+// The root command is written as <binary_name>.md and command groups use the configured index format
+func (i *Importer) Files(result *ImportResult) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	add := func(name string, cmd *ImportedCommand) error {
+		if _, exists := files[name]; exists {
+			return fmt.Errorf("%s: more than one command would be written to %s", cmd.Source, name)
+		}
+		content, err := renderImportedCommand(cmd)
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", cmd.Name, err)
+		}
+		files[name] = content
+		return nil
+	}
+
+	binaryName := i.config.BinaryName
+	if binaryName == "" {
+		binaryName = result.Root.Name
+	}
+	if err := add(binaryName+".md", result.Root); err != nil {
+		return nil, err
+	}
+
+	var addTree func(dir string, cmd *ImportedCommand) error
+	addTree = func(dir string, cmd *ImportedCommand) error {
+		for _, sub := range cmd.Subcommands {
+			if len(sub.Subcommands) == 0 {
+				if err := add(path.Join(dir, sub.Name+".md"), sub); err != nil {
+					return err
+				}
+				continue
+			}
+
+			groupDir := path.Join(dir, sub.Name)
+			if err := add(path.Join(groupDir, i.config.GetIndexPatterns(sub.Name)[0]), sub); err != nil {
+				return err
+			}
+			if err := addTree(groupDir, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := addTree("", result.Root); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Write writes the markdown of an import below dir and returns the paths written
+// Existing files are only overwritten with force; nothing is written if any would be
+func (i *Importer) Write(result *ImportResult, dir string, force bool) ([]string, error) {
+	files, err := i.Files(result)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for name := range files {
+		paths = append(paths, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	sort.Strings(paths)
+
+	if !force {
+		for _, p := range paths {
+			if _, err := os.Stat(p); err == nil {
+				return nil, fmt.Errorf("%s already exists, use --force to overwrite", p)
+			}
+		}
+	}
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, fmt.Errorf("creating directory for %s: %w", p, err)
+		}
+		if err := writeFileAtomic(p, content, 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", p, err)
+		}
+	}
+	return paths, nil
+}
+
+// renderImportedCommand renders the markdown file of an imported command
+func renderImportedCommand(cmd *ImportedCommand) ([]byte, error) {
+	fm := importFrontmatter{
+		Title: cmd.Title,
+		Command: importCommand{
+			Name:            cmd.Name,
+			Aliases:         cmd.Aliases,
+			Hidden:          cmd.Hidden,
+			Flags:           importFlags(cmd.Flags),
+			PersistentFlags: importFlags(cmd.PersistentFlags),
+		},
+	}
+	for _, arg := range cmd.Arguments {
+		fm.Command.Arguments = append(fm.Command.Arguments, importArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+			Type:        arg.Type,
+		})
+	}
+
+	frontmatter, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling frontmatter: %w", err)
+	}
+
+	heading := cmd.Title
+	if heading == "" {
+		heading = cmd.Name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\n%s---\n\n# %s\n", frontmatter, heading)
+	if description := strings.TrimSpace(cmd.Description); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+	if example := strings.Trim(cmd.Example, "\n"); strings.TrimSpace(example) != "" {
+		fmt.Fprintf(&b, "\n## Examples\n\n```bash\n%s\n```\n", example)
+	}
+	return []byte(b.String()), nil
+}
+
+// importFlags converts flags to their frontmatter form
+func importFlags(flags []Flag) []importFlag {
+	var result []importFlag
+	for _, f := range flags {
+		result = append(result, importFlag{
+			Name:              f.Name,
+			Shorthand:         f.Shorthand,
+			Description:       f.Description,
+			Type:              f.Type,
+			Default:           f.Default,
+			Required:          f.Required,
+			MutuallyExclusive: f.MutuallyExclusive,
+			RequiredTogether:  f.RequiredTogether,
+			OneRequired:       f.OneRequired,
+		})
+	}
+	return result
+}
@@ -0,0 +1,307 @@
+package adder
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// importSource is a small cobra-cli style project mixing package level commands and constructors
+var importSource = map[string]string{
+	"root.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+var cfgFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "myapp",
+	Short: "My application",
+	Long:  "My application does things.",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	rootCmd.AddCommand(serveCmd, newUserCmd())
+}
+`,
+	"serve.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+var serveCmd = &cobra.Command{
+	Use:     "serve [addr]",
+	Aliases: []string{"server", "s"},
+	Short:   "Start the " + "server",
+	Example: "  myapp serve :8080",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cmd.Flags().GetInt("port")
+		return err
+	},
+}
+
+func init() {
+	flags := serveCmd.Flags()
+	flags.IntP("port", "p", 8080, "Port to listen on")
+	flags.Bool("tls", false, "Serve over TLS")
+	flags.Duration("timeout", 0, "Request timeout")
+	flags.String("cert", defaultCert(), "Certificate file")
+	serveCmd.MarkFlagsRequiredTogether("tls", "cert")
+}
+
+func defaultCert() string { return "cert.pem" }
+`,
+	"user/user.go": `package user
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	cmd.AddCommand(newCreateCmd())
+	return cmd
+}
+
+func newCreateCmd() *cobra.Command {
+	var roles []string
+	cmd := &cobra.Command{
+		Use:    "create <name>",
+		Short:  "Create a user",
+		Hidden: true,
+	}
+	cmd.Flags().StringSliceVarP(&roles, "role", "r", []string{"viewer"}, "Roles to grant")
+	cmd.Flags().StringP("email", "e", "", "Email address")
+	_ = cmd.MarkFlagRequired("email")
+	return cmd
+}
+`,
+	"user_cmd.go": `package cmd
+
+import (
+	"example.com/myapp/cmd/user"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd() *cobra.Command { return user.NewCommand() }
+`,
+	"root_test.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+var testCmd = &cobra.Command{Use: "ignored"}
+`,
+}
+
+func writeImportSource(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range importSource {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestImporter_Import(t *testing.T) {
+	dir := writeImportSource(t)
+
+	result, err := NewImporter(DefaultConfig()).Import(dir)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	root := result.Root
+	if root.Name != "myapp" || root.Title != "My application" {
+		t.Fatalf("root = %q (%q), want myapp", root.Name, root.Title)
+	}
+	if result.Count() != 4 {
+		t.Errorf("Count() = %d, want 4", result.Count())
+	}
+	if len(root.PersistentFlags) != 1 || root.PersistentFlags[0].Name != "config" {
+		t.Errorf("root persistent flags = %+v, want config", root.PersistentFlags)
+	}
+
+	var names []string
+	for _, sub := range root.Subcommands {
+		names = append(names, sub.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"serve", "user"}) {
+		t.Fatalf("root subcommands = %v, want [serve user]", names)
+	}
+
+	serve := root.Subcommands[0]
+	if serve.Title != "Start the server" || !reflect.DeepEqual(serve.Aliases, []string{"server", "s"}) {
+		t.Errorf("serve = %q %v", serve.Title, serve.Aliases)
+	}
+	if len(serve.Arguments) != 1 || serve.Arguments[0].Name != "addr" || serve.Arguments[0].Required {
+		t.Errorf("serve arguments = %+v, want optional addr", serve.Arguments)
+	}
+	wantFlags := []Flag{
+		{Name: "port", Shorthand: "p", Description: "Port to listen on", Type: TypeInt, Default: 8080},
+		{Name: "tls", Description: "Serve over TLS", Type: TypeBool, RequiredTogether: []string{"cert"}},
+		{Name: "cert", Description: "Certificate file", Type: TypeString},
+	}
+	if !reflect.DeepEqual(serve.Flags, wantFlags) {
+		t.Errorf("serve flags = %+v\nwant %+v", serve.Flags, wantFlags)
+	}
+
+	user := root.Subcommands[1]
+	if len(user.Subcommands) != 1 {
+		t.Fatalf("user subcommands = %d, want 1", len(user.Subcommands))
+	}
+	create := user.Subcommands[0]
+	if !create.Hidden || len(create.Arguments) != 1 || !create.Arguments[0].Required {
+		t.Errorf("create = hidden %v, arguments %+v", create.Hidden, create.Arguments)
+	}
+	wantFlags = []Flag{
+		{Name: "role", Shorthand: "r", Description: "Roles to grant", Type: TypeStringArray, Default: []string{"viewer"}},
+		{Name: "email", Shorthand: "e", Description: "Email address", Type: TypeString, Required: true},
+	}
+	if !reflect.DeepEqual(create.Flags, wantFlags) {
+		t.Errorf("create flags = %+v\nwant %+v", create.Flags, wantFlags)
+	}
+
+	warnings := strings.Join(result.Warnings, "\n")
+	for _, want := range []string{"--timeout of unsupported type Duration", "default of flag --cert is not a literal"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings missing %q:\n%s", want, warnings)
+		}
+	}
+}
+
+func TestImporter_Import_NoCommands(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	if _, err := NewImporter(DefaultConfig()).Import(dir); err == nil {
+		t.Error("Import() expected an error for source without commands")
+	}
+}
+
+func TestImporter_Write(t *testing.T) {
+	tests := []struct {
+		indexFormat string
+		wantFiles   []string
+	}{
+		{indexFormat: "directory", wantFiles: []string{"myapp.md", "serve.md", "user/create.md", "user/user.md"}},
+		{indexFormat: "_index", wantFiles: []string{"myapp.md", "serve.md", "user/_index.md", "user/create.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.indexFormat, func(t *testing.T) {
+			src := writeImportSource(t)
+			out := t.TempDir()
+
+			config := DefaultConfig()
+			config.BinaryName = "myapp"
+			config.IndexFormat = tt.indexFormat
+			config.InputDir = out
+			config.OutputDir = filepath.Join(t.TempDir(), "generated")
+
+			importer := NewImporter(config)
+			result, err := importer.Import(src)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			paths, err := importer.Write(result, out, false)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			var got []string
+			for _, p := range paths {
+				rel, _ := filepath.Rel(out, p)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("Write() files = %v, want %v", got, tt.wantFiles)
+			}
+
+			if _, err := importer.Write(result, out, false); err == nil {
+				t.Error("Write() expected an error when files exist")
+			}
+			if _, err := importer.Write(result, out, true); err != nil {
+				t.Errorf("Write() with force error = %v", err)
+			}
+
+			// The imported markdown must generate without errors
+			generator := New(config)
+			if err := generator.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := generator.GenerateWithContext(context.Background()); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(generator.GetCommands()) != 4 {
+				t.Errorf("generated %d commands, want 4", len(generator.GetCommands()))
+			}
+		})
+	}
+}
+
+func TestImporter_RoundTripQuotes(t *testing.T) {
+	src := t.TempDir()
+	source := "package cmd\n\nimport \"github.com/spf13/cobra\"\n\nvar rootCmd = &cobra.Command{\n\tUse:   \"myapp\",\n\tShort: `Delete \"it\"`,\n}\n"
+	if err := os.WriteFile(filepath.Join(src, "root.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.BinaryName = "myapp"
+	config.InputDir = t.TempDir()
+	config.OutputDir = filepath.Join(t.TempDir(), "generated")
+
+	importer := NewImporter(config)
+	result, err := importer.Import(src)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if _, err := importer.Write(result, config.InputDir, false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := New(config).GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// The generated code must compile with the title as Short
+	path := filepath.Join(config.OutputDir, "myapp_generated.go")
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	var short string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if kv, ok := n.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Short" {
+				if lit, ok := kv.Value.(*ast.BasicLit); ok {
+					short, _ = strconv.Unquote(lit.Value)
+				}
+			}
+		}
+		return true
+	})
+	if short != `Delete "it"` {
+		t.Errorf("Short = %q, want %q", short, `Delete "it"`)
+	}
+}
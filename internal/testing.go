@@ -52,6 +52,8 @@ func stringContains(s, substr string) bool {
 }
 
 // RequestBuilder provides a fluent interface for building test requests
+//
+// Deprecated: use the generated <Name>RequestBuilder, which is type-safe and starts from the declared defaults.
 type RequestBuilder struct {
 	flags map[string]interface{}
 	args  map[string]interface{}
@@ -0,0 +1,203 @@
+package adder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LockFileName is the manifest in the output directory recording the inputs of every generated file
+// Generation skips outputs whose inputs are unchanged, so their modification times stay put
+const LockFileName = ".adder.lock"
+
+// lockFormatVersion is bumped when the lockfile layout changes; older lockfiles are ignored
+const lockFormatVersion = 1
+
+// lockFile is the content of .adder.lock
+type lockFile struct {
+	Version int         `yaml:"version"`
+	Outputs []lockEntry `yaml:"outputs"`
+}
+
+// lockEntry records everything a generated file was rendered from
+type lockEntry struct {
+	Output       string      `yaml:"output"`                 // Path relative to the output directory
+	Hash         string      `yaml:"hash"`                   // Hash of the generated content
+	Sources      []lockInput `yaml:"sources,omitempty"`      // Markdown files rendered into the output
+	Emitter      string      `yaml:"emitter,omitempty"`      // Name of the Emitter that wrote the output
+	Dependencies []lockInput `yaml:"dependencies,omitempty"` // Inputs from other files, e.g. persistent flags of parent commands
+	AdderVersion string      `yaml:"adder_version"`
+	TemplateHash string      `yaml:"template_hash"`
+	ConfigHash   string      `yaml:"config_hash"` // Settings that change the generated code, e.g. the package name
+}
+
+// lockInput is the content hash of one input, keyed by its path in the input directory
+type lockInput struct {
+	Path string `yaml:"path"`
+	Hash string `yaml:"hash"`
+}
+
+// sameInputs reports whether two entries were rendered from the same inputs
+func (e lockEntry) sameInputs(other lockEntry) bool {
+	e.Hash, other.Hash = "", ""
+	return reflect.DeepEqual(e, other)
+}
+
+// lockPath returns the path of the lockfile in the output directory
+func (g *Generator) lockPath() string {
+	return filepath.Join(g.config.OutputDir, LockFileName)
+}
+
+// readLock returns the entries of the lockfile keyed by output path
+// A missing, unreadable or outdated lockfile yields no entries, so every output is checked
+func (g *Generator) readLock() map[string]lockEntry {
+	entries := make(map[string]lockEntry)
+
+	data, err := fs.ReadFile(g.output, LockFileName)
+	if err != nil {
+		return entries
+	}
+	var lock lockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil || lock.Version != lockFormatVersion {
+		return entries
+	}
+
+	for _, entry := range lock.Outputs {
+		entries[entry.Output] = entry
+	}
+	return entries
+}
+
+// writeLock writes the lockfile, leaving it untouched when its content is unchanged
+func (g *Generator) writeLock(entries []lockEntry) error {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Output < entries[j].Output })
+
+	data, err := yaml.Marshal(lockFile{Version: lockFormatVersion, Outputs: entries})
+	if err != nil {
+		return fmt.Errorf("marshaling lockfile: %w", err)
+	}
+	content := append([]byte("# Generated by adder to skip unchanged outputs. DO NOT EDIT.\n"), data...)
+
+	if current, err := fs.ReadFile(g.output, LockFileName); err == nil && string(current) == string(content) {
+		return nil
+	}
+	if err := g.output.WriteFile(LockFileName, content, 0644); err != nil {
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	return nil
+}
+
+// lockState holds what every lockfile entry of one generation is compared against
+type lockState struct {
+	previous     map[string]lockEntry     // Entries of the existing lockfile, keyed by output
+	dependencies map[*Command][]lockInput // Inherited inputs of each command
+	adderVersion string
+	templateHash string
+}
+
+// newLockState reads the lockfile and hashes the inputs shared between outputs once
+func (g *Generator) newLockState() (*lockState, error) {
+	templates, err := g.templates()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &lockState{
+		previous:     g.readLock(),
+		dependencies: make(map[*Command][]lockInput),
+		adderVersion: generatorVersion(),
+		templateHash: templates.hash,
+	}
+
+	// Persistent flags of parent commands apply to their subcommands as well
+	persistentHashes := make(map[*CommandNode]string)
+	err = BuildCommandTree(g.config.BinaryName, g.commands).Walk(func(node *CommandNode) error {
+		for parent := node.Parent; parent != nil; parent = parent.Parent {
+			if len(parent.Command.PersistentFlags) == 0 {
+				continue
+			}
+			hash, ok := persistentHashes[parent]
+			if !ok {
+				flags, err := yaml.Marshal(parent.Command.PersistentFlags)
+				if err != nil {
+					return fmt.Errorf("hashing persistent flags of %s: %w", parent.CommandPath(), err)
+				}
+				hash = hashContent(flags)
+				persistentHashes[parent] = hash
+			}
+			state.dependencies[node.Command] = append(state.dependencies[node.Command], lockInput{Path: filepath.ToSlash(parent.Command.FilePath), Hash: hash})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// lockEntryFor records the inputs of an output file; its Hash is set once the content is known
+func (g *Generator) lockEntryFor(inputFS fs.FS, filename string, commands []*Command, state *lockState) (lockEntry, error) {
+	output, err := filepath.Rel(g.config.OutputDir, filename)
+	if err != nil {
+		output = filename
+	}
+
+	entry := lockEntry{
+		Output:       filepath.ToSlash(output),
+		AdderVersion: state.adderVersion,
+		TemplateHash: state.templateHash,
+	}
+
+	packageName := g.config.Package
+	for _, cmd := range commands {
+		content, err := fs.ReadFile(inputFS, cmd.FilePath)
+		if err != nil {
+			return lockEntry{}, fmt.Errorf("reading %s: %w", cmd.FilePath, err)
+		}
+		entry.Sources = append(entry.Sources, lockInput{Path: filepath.ToSlash(cmd.FilePath), Hash: hashContent(content)})
+		entry.Dependencies = append(entry.Dependencies, state.dependencies[cmd]...)
+		packageName = g.config.GetPackageName(cmd.FilePath)
+	}
+
+	settings := fmt.Sprintf("binary_name=%s\nindex_format=%s\npackage=%s\n", g.config.BinaryName, g.config.IndexFormat, packageName)
+	entry.ConfigHash = hashContent([]byte(settings))
+
+	return entry, nil
+}
+
+// hashContent returns the sha256 hash of content as "sha256:<hex>"
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// pseudoVersion matches versions Go stamps on untagged builds, e.g. v0.0.0-20240101120000-abcdef123456+dirty
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}|\+dirty$`)
+
+// generatorVersion returns the version of the adder module doing the generation
+// Released builds report their module version; development builds fall back to Version(),
+// so the lockfile does not change with every commit
+func generatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version()
+	}
+	for _, module := range append([]*debug.Module{&info.Main}, info.Deps...) {
+		if module.Path != "github.com/jrschumacher/adder" {
+			continue
+		}
+		if module.Version != "" && module.Version != "(devel)" && !pseudoVersion.MatchString(module.Version) {
+			return module.Version
+		}
+	}
+	return Version()
+}
@@ -0,0 +1,143 @@
+package adder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lockTestMarkdown is a root command with a persistent flag and one subcommand
+var lockTestMarkdown = map[string]string{
+	"app.md": `---
+title: App
+command:
+  name: app
+  persistent_flags:
+    - name: config
+      type: string
+---
+
+# App
+`,
+	"app/serve.md": `---
+title: Serve
+command:
+  name: serve
+---
+
+# Serve
+`,
+}
+
+// generateForLockTest runs a generation and returns the number of files it skipped
+func generateForLockTest(t *testing.T, config *Config) int {
+	t.Helper()
+	generator := New(config)
+	if err := generator.GenerateWithContext(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return generator.GetStats()["skipped_files"]
+}
+
+// backdate sets the modification time of files to an hour ago and returns it
+func backdate(t *testing.T, paths ...string) time.Time {
+	t.Helper()
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, path := range paths {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to backdate %s: %v", path, err)
+		}
+	}
+	return old
+}
+
+// assertRewritten checks whether a backdated file was written again
+func assertRewritten(t *testing.T, path string, backdated time.Time, want bool) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	if rewritten := !info.ModTime().Equal(backdated); rewritten != want {
+		t.Errorf("%s rewritten = %v, want %v", filepath.Base(path), rewritten, want)
+	}
+}
+
+func TestGenerator_Lockfile(t *testing.T) {
+	config := DefaultConfig()
+	config.BinaryName = "app"
+	config.InputDir = t.TempDir()
+	config.OutputDir = t.TempDir()
+	for name, content := range lockTestMarkdown {
+		writeTestFile(t, filepath.Join(config.InputDir, filepath.FromSlash(name)), content)
+	}
+
+	root := filepath.Join(config.OutputDir, "app_generated.go")
+	serve := filepath.Join(config.OutputDir, "app", "serve_generated.go")
+
+	if skipped := generateForLockTest(t, config); skipped != 0 {
+		t.Errorf("first generation skipped %d files, want 0", skipped)
+	}
+	lock, err := os.ReadFile(filepath.Join(config.OutputDir, LockFileName))
+	if err != nil {
+		t.Fatalf("Expected %s to be written: %v", LockFileName, err)
+	}
+	for _, want := range []string{"output: app/serve_generated.go", "path: app/serve.md", "dependencies:", "path: app.md", "template_hash: sha256:"} {
+		if !strings.Contains(string(lock), want) {
+			t.Errorf("lockfile missing %q:\n%s", want, lock)
+		}
+	}
+
+	// Nothing changed: no file is written
+	old := backdate(t, root, serve)
+	if skipped := generateForLockTest(t, config); skipped != 2 {
+		t.Errorf("unchanged generation skipped %d files, want 2", skipped)
+	}
+	assertRewritten(t, root, old, false)
+	assertRewritten(t, serve, old, false)
+
+	// Editing the body of the root command leaves the subcommand alone
+	writeTestFile(t, filepath.Join(config.InputDir, "app.md"), strings.Replace(lockTestMarkdown["app.md"], "title: App", "title: The app", 1))
+	generateForLockTest(t, config)
+	assertRewritten(t, root, old, true)
+	assertRewritten(t, serve, old, false)
+
+	// Changing a persistent flag of the parent is a dependency change of the subcommand
+	before, _ := os.ReadFile(filepath.Join(config.OutputDir, LockFileName))
+	writeTestFile(t, filepath.Join(config.InputDir, "app.md"), strings.Replace(lockTestMarkdown["app.md"], "name: config", "name: profile", 1))
+	generateForLockTest(t, config)
+	after, _ := os.ReadFile(filepath.Join(config.OutputDir, LockFileName))
+	if string(before) == string(after) {
+		t.Error("lockfile should record the new persistent flags")
+	}
+
+	// Hand edits to a generated file are overwritten
+	writeTestFile(t, serve, "package generated\n")
+	if skipped := generateForLockTest(t, config); skipped != 1 {
+		t.Errorf("generation after a hand edit skipped %d files, want 1", skipped)
+	}
+	if content, _ := os.ReadFile(serve); !strings.Contains(string(content), "Code generated by adder") {
+		t.Error("hand edited file was not regenerated")
+	}
+
+	// A corrupt lockfile is ignored; identical content is still not rewritten
+	writeTestFile(t, filepath.Join(config.OutputDir, LockFileName), "{not yaml")
+	old = backdate(t, root, serve)
+	generateForLockTest(t, config)
+	assertRewritten(t, root, old, false)
+	assertRewritten(t, serve, old, false)
+}
+
+func TestGeneratorVersion(t *testing.T) {
+	for _, version := range []string{"v0.0.0-20240101120000-abcdef123456", "v1.2.1-0.20240101120000-abcdef123456+dirty"} {
+		if !pseudoVersion.MatchString(version) {
+			t.Errorf("pseudoVersion should match %q", version)
+		}
+	}
+	if pseudoVersion.MatchString("v1.2.0") {
+		t.Error("pseudoVersion should not match a release")
+	}
+}
@@ -0,0 +1,256 @@
+package adder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ManOptions configures the header of generated man pages
+type ManOptions struct {
+	Section string    // Manual section, defaults to 1
+	Date    time.Time // Date in the page footer, omitted when zero
+	Source  string    // Source of the program, e.g. "adder 1.2.0"
+	Manual  string    // Title of the manual, e.g. "Adder Manual"
+}
+
+// ManPages renders a roff man page for every command of the tree, keyed by file name
+// Pages are named after the command path, e.g. adder-generate.1
+func ManPages(root *CommandNode, opts ManOptions) (map[string][]byte, error) {
+	if opts.Section == "" {
+		opts.Section = "1"
+	}
+
+	pages := make(map[string][]byte)
+	err := root.Walk(func(node *CommandNode) error {
+		name := manPageName(node) + "." + opts.Section
+		if _, exists := pages[name]; exists {
+			return fmt.Errorf("%s: more than one command would be written to %s", node.CommandPath(), name)
+		}
+		pages[name] = renderManPage(node, opts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// WriteManPages writes the man pages of the command tree to dir and returns the paths written
+func WriteManPages(root *CommandNode, dir string, opts ManOptions) ([]string, error) {
+	pages, err := ManPages(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return writeFileMap(dir, pages)
+}
+
+// manPageName returns the page name of a command, e.g. adder-generate
+func manPageName(node *CommandNode) string {
+	return strings.Join(node.Path, "-")
+}
+
+// renderManPage renders the man page of a single command
+func renderManPage(node *CommandNode, opts ManOptions) []byte {
+	cmd := node.Command
+	var b strings.Builder
+
+	date := ""
+	if !opts.Date.IsZero() {
+		date = opts.Date.Format("Jan 2006")
+	}
+	fmt.Fprintf(&b, ".TH %s %s %s %s %s\n",
+		roffQuote(strings.ToUpper(manPageName(node))), roffQuote(opts.Section),
+		roffQuote(date), roffQuote(opts.Source), roffQuote(opts.Manual))
+	b.WriteString(".nh\n.ad l\n")
+
+	b.WriteString(".SH NAME\n")
+	if cmd.Title != "" {
+		fmt.Fprintf(&b, "%s \\- %s\n", roffEscape(manPageName(node)), roffEscape(cmd.Title))
+	} else {
+		fmt.Fprintf(&b, "%s\n", roffEscape(manPageName(node)))
+	}
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, "\\fB%s\\fP", roffEscape(node.CommandPath()))
+	for _, part := range strings.Fields(strings.TrimPrefix(node.Usage(), node.CommandPath())) {
+		fmt.Fprintf(&b, " \\fI%s\\fP", roffEscape(part))
+	}
+	b.WriteString("\n")
+
+	if len(cmd.Aliases) > 0 {
+		b.WriteString(".PP\nAliases: ")
+		for i, alias := range cmd.Aliases {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "\\fB%s\\fP", roffEscape(alias))
+		}
+		b.WriteString("\n")
+	}
+
+	if body := docsBody(cmd.Description); body != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		b.WriteString(markdownToRoff(body))
+	}
+
+	if len(cmd.Arguments) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range cmd.Arguments {
+			fmt.Fprintf(&b, ".TP\n\\fI%s\\fP\n", roffEscape(arg.Name))
+			description := arg.Description
+			if arg.Required {
+				description = strings.TrimSpace(description + " (required)")
+			}
+			fmt.Fprintf(&b, "%s\n", roffEscape(description))
+		}
+	}
+
+	writeManOptions(&b, "OPTIONS", append(append(append([]Flag{}, cmd.Flags...), cmd.PersistentFlags...), generatedFlags(cmd)...))
+	writeManOptions(&b, "OPTIONS INHERITED FROM PARENT COMMANDS", node.InheritedFlags())
+
+	if len(cmd.Errors) > 0 {
+		b.WriteString(".SH EXIT STATUS\n")
+		for _, e := range cmd.Errors {
+			fmt.Fprintf(&b, ".TP\n\\fB%d\\fP\n%s\n", e.Code, roffEscape(strings.TrimSpace(e.Name+": "+e.Description)))
+		}
+	}
+
+	var related []*CommandNode
+	if node.Parent != nil {
+		related = append(related, node.Parent)
+	}
+	related = append(related, node.Children...)
+	if len(related) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		for i, other := range related {
+			separator := ","
+			if i == len(related)-1 {
+				separator = ""
+			}
+			fmt.Fprintf(&b, ".BR %s (%s)%s\n", roffEscape(manPageName(other)), opts.Section, separator)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// writeManOptions writes a section describing flags with their defaults and enum values
+func writeManOptions(b *strings.Builder, heading string, flags []Flag) {
+	if len(flags) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, ".SH %s\n", heading)
+	for _, f := range flags {
+		b.WriteString(".TP\n")
+		if f.Shorthand != "" {
+			fmt.Fprintf(b, "\\fB\\-%s\\fP, ", roffEscape(f.Shorthand))
+		}
+		fmt.Fprintf(b, "\\fB\\-\\-%s\\fP", roffEscape(f.Name))
+		if f.Type != TypeBool {
+			fmt.Fprintf(b, " \\fI%s\\fP", roffEscape(f.Type))
+		}
+		b.WriteString("\n")
+
+		var notes []string
+		if f.Required {
+			notes = append(notes, "required")
+		}
+		if defaultValue := formatFlagDefault(f); defaultValue != "" {
+			notes = append(notes, "default: "+defaultValue)
+		}
+		if len(f.Enum) > 0 {
+			notes = append(notes, "one of: "+strings.Join(f.Enum, ", "))
+		}
+
+		description := f.Description
+		if len(notes) > 0 {
+			description = strings.TrimSpace(description + " (" + strings.Join(notes, "; ") + ")")
+		}
+		fmt.Fprintf(b, "%s\n", roffEscape(description))
+	}
+}
+
+var (
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+	markdownBold   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+	markdownItem   = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+`)
+	markdownHeader = regexp.MustCompile(`^#{2,6}\s+`)
+)
+
+// markdownToRoff converts a markdown body to roff
+// Headings become subsections, fenced code blocks are kept verbatim and list items become indented paragraphs
+func markdownToRoff(markdown string) string {
+	var b strings.Builder
+	inCode := false
+	paragraph := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				b.WriteString(".fi\n.RE\n")
+			} else {
+				b.WriteString(".PP\n.RS 4\n.nf\n")
+			}
+			inCode = !inCode
+			paragraph = false
+			continue
+		}
+		if inCode {
+			fmt.Fprintf(&b, "%s\n", roffEscape(line))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			paragraph = false
+		case strings.HasPrefix(trimmed, "# "):
+			// The page title already names the command
+		case markdownHeader.MatchString(trimmed):
+			fmt.Fprintf(&b, ".SS %s\n", roffEscape(markdownHeader.ReplaceAllString(trimmed, "")))
+			paragraph = false
+		case markdownItem.MatchString(line):
+			fmt.Fprintf(&b, ".IP \\(bu 2\n%s\n", roffInline(markdownItem.ReplaceAllString(line, "")))
+			paragraph = true
+		default:
+			if !paragraph {
+				b.WriteString(".PP\n")
+				paragraph = true
+			}
+			fmt.Fprintf(&b, "%s\n", roffInline(trimmed))
+		}
+	}
+	if inCode {
+		b.WriteString(".fi\n.RE\n")
+	}
+	return b.String()
+}
+
+// roffInline escapes a line of markdown text and converts inline code, bold text and links
+func roffInline(text string) string {
+	text = roffEscape(text)
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownCode.ReplaceAllString(text, `\fB$1\fP`)
+	text = markdownBold.ReplaceAllString(text, `\fB$1\fP`)
+	return text
+}
+
+// roffEscape escapes text so roff prints it literally
+func roffEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text
+}
+
+// roffQuote quotes a macro argument
+func roffQuote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,68 @@
+package adder
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManPages(t *testing.T) {
+	pages, err := ManPages(docsTestTree(), ManOptions{
+		Date:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Source: "app 1.0",
+		Manual: "App Manual",
+	})
+	if err != nil {
+		t.Fatalf("ManPages() error = %v", err)
+	}
+
+	var names []string
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "app-user-create.1,app-user.1,app.1" {
+		t.Fatalf("pages = %v", names)
+	}
+
+	create := string(pages["app-user-create.1"])
+	for _, want := range []string{
+		`.TH "APP-USER-CREATE" "1" "Mar 2026" "app 1.0" "App Manual"`,
+		".SH NAME\napp\\-user\\-create \\- Create a user\n",
+		".SH SYNOPSIS\n\\fBapp user create\\fP \\fI<name>\\fP \\fI[flags]\\fP\n",
+		"Aliases: \\fBadd\\fP",
+		".SH DESCRIPTION\n.PP\nCreates a user.\n",
+		".TP\n\\fIname\\fP\nUser name (required)\n",
+		".SH OPTIONS\n.TP\n\\fB\\-\\-role\\fP \\fIstring\\fP\nRole | level (default: viewer; one of: viewer, admin)\n",
+		"Email address (required)",
+		".TP\n\\fB\\-\\-from\\-file\\fP \\fIstring\\fP\nPopulate the request from a JSON or YAML file (use \\- for stdin)\n",
+		".TP\n\\fB\\-y\\fP, \\fB\\-\\-yes\\fP\nSkip the confirmation prompt (default: false)\n",
+		".TP\n\\fB\\-o\\fP, \\fB\\-\\-output\\fP \\fIstring\\fP\nOutput format (json, yaml, table, or ndjson) (default: json)\n",
+		".SH OPTIONS INHERITED FROM PARENT COMMANDS\n.TP\n\\fB\\-v\\fP, \\fB\\-\\-verbose\\fP\nVerbose output (default: false)\n",
+		".SH EXIT STATUS\n.TP\n\\fB4\\fP\nexists: User already exists\n",
+		".SH SEE ALSO\n.BR app\\-user (1)\n",
+	} {
+		if !strings.Contains(create, want) {
+			t.Errorf("app-user-create.1 missing %q:\n%s", want, create)
+		}
+	}
+
+	user := string(pages["app-user.1"])
+	if !strings.Contains(user, ".BR app (1),\n.BR app\\-user\\-create (1)\n") {
+		t.Errorf("app-user.1 SEE ALSO should list the parent and subcommands:\n%s", user)
+	}
+}
+
+func TestMarkdownToRoff(t *testing.T) {
+	markdown := "# Title\n\nFirst `code` and **bold** with a [link](https://example.com).\nSame paragraph.\n\n## Examples\n\n```bash\n.hidden --flag\n```\n\n- item one\n- item two"
+
+	want := ".PP\nFirst \\fBcode\\fP and \\fBbold\\fP with a link.\nSame paragraph.\n" +
+		".SS Examples\n" +
+		".PP\n.RS 4\n.nf\n\\&.hidden \\-\\-flag\n.fi\n.RE\n" +
+		".IP \\(bu 2\nitem one\n.IP \\(bu 2\nitem two\n"
+
+	if got := markdownToRoff(markdown); got != want {
+		t.Errorf("markdownToRoff() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package adder
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// HandlerFunc is the untyped form of a generated handler
+// Middleware receives the request as an adder.Request; type assert it for command specific fields
+type HandlerFunc func(cmd *cobra.Command, req Request) error
+
+// Middleware wraps a handler with cross-cutting behavior (logging, timing, auth, recovery)
+// Middleware runs after the request has been populated and validated
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// CommandOption configures a generated command
+type CommandOption func(*CommandOptions)
+
+// CommandOptions holds the options a generated command was created with
+type CommandOptions struct {
+	Middleware []Middleware
+	Prompter   *Prompter
+}
+
+// NewCommandOptions applies opts to a new CommandOptions
+func NewCommandOptions(opts ...CommandOption) *CommandOptions {
+	options := &CommandOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithMiddleware adds middleware to a single generated command
+func WithMiddleware(middleware ...Middleware) CommandOption {
+	return func(o *CommandOptions) {
+		o.Middleware = append(o.Middleware, middleware...)
+	}
+}
+
+// Wrap wraps handler with the setup hooks and middleware that apply to cmd
+// Setup hooks run first, then global middleware, path middleware in registration order, and the command's own
+func (o *CommandOptions) Wrap(cmd *cobra.Command, handler HandlerFunc) HandlerFunc {
+	chain := append([]Middleware{runSetup}, registeredMiddleware(CommandPath(cmd))...)
+	if o != nil {
+		chain = append(chain, o.Middleware...)
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+	return handler
+}
+
+// pathMiddleware is middleware registered for a subtree of commands
+type pathMiddleware struct {
+	path       string
+	middleware Middleware
+}
+
+var (
+	middlewareMu sync.RWMutex
+	middlewares  []pathMiddleware
+)
+
+// Use registers middleware for every generated command
+func Use(middleware ...Middleware) {
+	UsePath("", middleware...)
+}
+
+// UsePath registers middleware for the command at path and all of its subcommands
+// The path excludes the root command, e.g. "profile" covers "profile create" and "profile delete"
+func UsePath(path string, middleware ...Middleware) {
+	path = strings.Join(strings.Fields(path), " ")
+
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	for _, mw := range middleware {
+		middlewares = append(middlewares, pathMiddleware{path: path, middleware: mw})
+	}
+}
+
+// registeredMiddleware returns the registered middleware that applies to a command path
+func registeredMiddleware(path string) []Middleware {
+	middlewareMu.RLock()
+	defer middlewareMu.RUnlock()
+
+	var chain []Middleware
+	for _, global := range []bool{true, false} {
+		for _, pm := range middlewares {
+			if (pm.path == "") != global {
+				continue
+			}
+			if pm.path == "" || path == pm.path || strings.HasPrefix(path, pm.path+" ") {
+				chain = append(chain, pm.middleware)
+			}
+		}
+	}
+	return chain
+}
+
+// CommandPath returns the space separated path of cmd without the root command
+func CommandPath(cmd *cobra.Command) string {
+	parts := strings.Fields(cmd.CommandPath())
+	if len(parts) <= 1 {
+		return ""
+	}
+	return strings.Join(parts[1:], " ")
+}
+
+// Recover is middleware that turns a panic in the handler into an error
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(cmd *cobra.Command, req Request) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%s: panic: %v", cmd.CommandPath(), r)
+				}
+			}()
+			return next(cmd, req)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package adder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// resetMiddleware clears the registered middleware when the test finishes
+func resetMiddleware(t *testing.T) {
+	t.Helper()
+	middlewareMu.Lock()
+	saved := middlewares
+	middlewares = nil
+	middlewareMu.Unlock()
+
+	t.Cleanup(func() {
+		middlewareMu.Lock()
+		middlewares = saved
+		middlewareMu.Unlock()
+	})
+}
+
+func TestCommandOptions_Wrap(t *testing.T) {
+	resetMiddleware(t)
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(cmd *cobra.Command, req Request) error {
+				calls = append(calls, name)
+				return next(cmd, req)
+			}
+		}
+	}
+
+	root := &cobra.Command{Use: "app"}
+	profile := &cobra.Command{Use: "profile"}
+	create := &cobra.Command{Use: "create"}
+	other := &cobra.Command{Use: "other"}
+	root.AddCommand(profile, other)
+	profile.AddCommand(create)
+
+	// Path middleware registered before global middleware still runs after it
+	UsePath("profile", record("profile"))
+	Use(record("global"))
+	UsePath("other", record("other"))
+	UsePath("profile create", record("create"))
+
+	tests := []struct {
+		name string
+		cmd  *cobra.Command
+		opts []CommandOption
+		want string
+	}{
+		{name: "subtree", cmd: create, opts: []CommandOption{WithMiddleware(record("command"))}, want: "global,profile,create,command,handler"},
+		{name: "subtree root", cmd: profile, want: "global,profile,handler"},
+		{name: "sibling path", cmd: other, want: "global,other,handler"},
+		{name: "root command", cmd: root, want: "global,handler"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			handler := NewCommandOptions(tt.opts...).Wrap(tt.cmd, func(cmd *cobra.Command, req Request) error {
+				calls = append(calls, "handler")
+				return nil
+			})
+			if err := handler(tt.cmd, nil); err != nil {
+				t.Fatalf("handler failed: %v", err)
+			}
+			if got := strings.Join(calls, ","); got != tt.want {
+				t.Errorf("calls = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	cmd := &cobra.Command{Use: "app"}
+	handler := Recover()(func(cmd *cobra.Command, req Request) error {
+		panic("boom")
+	})
+
+	err := handler(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "app: panic: boom") {
+		t.Errorf("error = %v, want recovered panic", err)
+	}
+}
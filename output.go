@@ -0,0 +1,243 @@
+package adder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats supported by generated commands
+const (
+	OutputFormatJSON   = "json"
+	OutputFormatYAML   = "yaml"
+	OutputFormatTable  = "table"
+	OutputFormatNDJSON = "ndjson"
+)
+
+// OutputFlag is the flag generated commands with a declared output use to select the format
+const OutputFlag = "output"
+
+// OutputFormats lists the supported output formats
+var OutputFormats = []string{OutputFormatJSON, OutputFormatYAML, OutputFormatTable, OutputFormatNDJSON}
+
+// outputUsage is the help text of --output
+var outputUsage = "Output format (" + joinEnumValues(OutputFormats, "or") + ")"
+
+// Column describes a table column rendered from a result field
+type Column struct {
+	Field  string // json name of the result field
+	Header string // column header
+}
+
+// Printer renders command results in one of the supported output formats
+type Printer struct {
+	Format  string
+	Columns []Column
+}
+
+// NewPrinter creates a printer for the given format and table columns
+func NewPrinter(format string, columns ...Column) (*Printer, error) {
+	if err := ValidateEnum(OutputFlag, format, OutputFormats); err != nil {
+		return nil, err
+	}
+	return &Printer{Format: format, Columns: columns}, nil
+}
+
+// AddOutputFlag registers the --output flag on a generated command
+func AddOutputFlag(cmd *cobra.Command, defaultFormat string) {
+	cmd.Flags().StringP(OutputFlag, "o", defaultFormat, outputUsage)
+}
+
+// CommandPrinter creates a printer for the format selected with the command's --output flag
+// An unsupported format is reported as a *UsageError
+func CommandPrinter(cmd *cobra.Command, columns ...Column) (*Printer, error) {
+	format, err := cmd.Flags().GetString(OutputFlag)
+	if err != nil {
+		return nil, err
+	}
+	printer, err := NewPrinter(format, columns...)
+	if err != nil {
+		return nil, &UsageError{Err: err}
+	}
+	return printer, nil
+}
+
+// Print renders a result (a struct or a slice of structs) to w
+// Nil results print nothing; a nil slice is an empty list, e.g. [] with json or only the header with table
+func (p *Printer) Print(w io.Writer, result interface{}) error {
+	if v := reflect.ValueOf(result); v.Kind() == reflect.Slice && v.IsNil() {
+		result = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+
+	// Round-trip through JSON so the result's json tags drive every format
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+	if string(data) == "null" {
+		return nil
+	}
+
+	switch p.Format {
+	case OutputFormatJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return fmt.Errorf("formatting result: %w", err)
+		}
+		buf.WriteByte('\n')
+		_, err := w.Write(buf.Bytes())
+		return err
+
+	case OutputFormatNDJSON:
+		rows, err := resultRows(data)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if _, err := fmt.Fprintf(w, "%s\n", row); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case OutputFormatYAML:
+		value, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+		if err != nil {
+			return fmt.Errorf("converting result: %w", err)
+		}
+		out, err := yaml.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encoding result: %w", err)
+		}
+		_, err = w.Write(out)
+		return err
+
+	case OutputFormatTable:
+		return p.printTable(w, data)
+
+	default:
+		return ValidateEnum(OutputFlag, p.Format, OutputFormats)
+	}
+}
+
+// printTable renders the result rows as an aligned table
+func (p *Printer) printTable(w io.Writer, data []byte) error {
+	rows, err := resultRows(data)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, len(p.Columns))
+	for i, column := range p.Columns {
+		headers[i] = column.Header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		var fields map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(row))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return fmt.Errorf("table output requires object results: %w", err)
+		}
+
+		values := make([]string, len(p.Columns))
+		for i, column := range p.Columns {
+			values[i] = formatCell(fields[column.Field])
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// resultRows splits an encoded result into one encoded row per item
+func resultRows(data []byte) ([]json.RawMessage, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var rows []json.RawMessage
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("decoding result: %w", err)
+		}
+		return rows, nil
+	}
+	return []json.RawMessage{data}, nil
+}
+
+// formatCell formats a decoded JSON value for a table cell
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatCell(item)
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// decodeOrdered decodes a JSON value, keeping object keys in their encoded order
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var object yaml.MapSlice
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				object = append(object, yaml.MapItem{Key: keyToken, Value: value})
+			}
+			_, err := decoder.Token() // closing brace
+			return object, err
+		case '[':
+			list := []interface{}{}
+			for decoder.More() {
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			_, err := decoder.Token() // closing bracket
+			return list, err
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+
+	return token, nil
+}
@@ -0,0 +1,105 @@
+package adder
+
+import (
+	"bytes"
+	"testing"
+)
+
+type outputTestResult struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+func TestPrinter_Print(t *testing.T) {
+	columns := []Column{{Field: "name", Header: "NAME"}, {Field: "tags", Header: "TAGS"}}
+	list := []outputTestResult{
+		{Name: "alpha", Count: 1, Tags: []string{"a", "b"}},
+		{Name: "beta", Count: 2},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		result interface{}
+		want   string
+	}{
+		{
+			name:   "json object",
+			format: OutputFormatJSON,
+			result: &list[0],
+			want:   "{\n  \"name\": \"alpha\",\n  \"count\": 1,\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n",
+		},
+		{
+			name:   "yaml keeps field order",
+			format: OutputFormatYAML,
+			result: &list[1],
+			want:   "name: beta\ncount: 2\ntags: null\n",
+		},
+		{
+			name:   "ndjson list",
+			format: OutputFormatNDJSON,
+			result: list,
+			want:   "{\"name\":\"alpha\",\"count\":1,\"tags\":[\"a\",\"b\"]}\n{\"name\":\"beta\",\"count\":2,\"tags\":null}\n",
+		},
+		{
+			name:   "table list",
+			format: OutputFormatTable,
+			result: list,
+			want:   "NAME   TAGS\nalpha  a,b\nbeta   \n",
+		},
+		{
+			name:   "json empty list",
+			format: OutputFormatJSON,
+			result: []outputTestResult(nil),
+			want:   "[]\n",
+		},
+		{
+			name:   "yaml empty list",
+			format: OutputFormatYAML,
+			result: []outputTestResult(nil),
+			want:   "[]\n",
+		},
+		{
+			name:   "ndjson empty list",
+			format: OutputFormatNDJSON,
+			result: []outputTestResult(nil),
+			want:   "",
+		},
+		{
+			name:   "table empty list prints the header",
+			format: OutputFormatTable,
+			result: []outputTestResult(nil),
+			want:   "NAME  TAGS\n",
+		},
+		{
+			name:   "nil result prints nothing",
+			format: OutputFormatTable,
+			result: (*outputTestResult)(nil),
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer, err := NewPrinter(tt.format, columns...)
+			if err != nil {
+				t.Fatalf("NewPrinter() failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := printer.Print(&buf, tt.result); err != nil {
+				t.Fatalf("Print() failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Print() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPrinter_InvalidFormat(t *testing.T) {
+	if _, err := NewPrinter("xml"); err == nil {
+		t.Errorf("NewPrinter() expected error for unsupported format")
+	}
+}
@@ -0,0 +1,173 @@
+package adder
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// OutputFS is the file system generated files are written to
+// Names are slash separated and relative to the output directory, as for fs.FS
+type OutputFS interface {
+	fs.FS
+	// WriteFile replaces the content of name, creating parent directories as needed
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Remove deletes a file or an empty directory
+	Remove(name string) error
+}
+
+// dirOutputFS writes to a directory on disk
+type dirOutputFS struct {
+	dir string
+	fs.FS
+}
+
+// DirOutputFS returns an OutputFS for the directory dir
+// Files are written atomically, so an interrupted generation never leaves a partially written file
+func DirOutputFS(dir string) OutputFS {
+	return dirOutputFS{dir: dir, FS: os.DirFS(dir)}
+}
+
+func (d dirOutputFS) path(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(d.dir, filepath.FromSlash(name)), nil
+}
+
+func (d dirOutputFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	filename, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, data, perm)
+}
+
+func (d dirOutputFS) Remove(name string) error {
+	filename, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(filename)
+}
+
+// MemoryFS is an OutputFS that keeps generated files in memory
+// It is safe for concurrent use, so tools and tests can generate without touching disk
+type MemoryFS struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+// NewMemoryFS returns an empty MemoryFS
+func NewMemoryFS() *MemoryFS {
+	return &MemoryFS{files: fstest.MapFS{}}
+}
+
+// Open implements fs.FS; directories are implied by the files below them
+func (m *MemoryFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+// WriteFile implements OutputFS
+func (m *MemoryFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Open files keep reading the content they were opened with
+	m.files[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: perm}
+	return nil
+}
+
+// Remove implements OutputFS
+func (m *MemoryFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.files[name]; ok {
+		delete(m.files, name)
+		return nil
+	}
+	for file := range m.files {
+		if strings.HasPrefix(file, name+"/") {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+	// Directories only exist while they have files, so an emptied one is already gone
+	return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+}
+
+// Files returns the content of every file, keyed by name
+func (m *MemoryFS) Files() map[string][]byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	files := make(map[string][]byte, len(m.files))
+	for name, file := range m.files {
+		files[name] = append([]byte(nil), file.Data...)
+	}
+	return files
+}
+
+// Names returns the names of every file in sorted order
+func (m *MemoryFS) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// outputName returns the name in the output FS of a path below the output directory
+func (g *Generator) outputName(filename string) (string, error) {
+	rel, err := filepath.Rel(filepath.Clean(g.config.OutputDir), filepath.Clean(filename))
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(rel)
+	if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+		return "", &fs.PathError{Op: "open", Path: filename, Err: errors.New("outside the output directory")}
+	}
+	return name, nil
+}
+
+// readOutput reads a file below the output directory from the output FS
+func (g *Generator) readOutput(filename string) ([]byte, error) {
+	name, err := g.outputName(filename)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(g.output, name)
+}
+
+// removeOutput deletes a file or an empty directory below the output directory from the output FS
+func (g *Generator) removeOutput(filename string) error {
+	name, err := g.outputName(filename)
+	if err != nil {
+		return err
+	}
+	return g.output.Remove(name)
+}
+
+// SetOutputFS sets the file system generated files are written to instead of the output directory
+// Paths reported by the generator still start with the configured output directory
+func (g *Generator) SetOutputFS(fsys OutputFS) {
+	g.output = fsys
+}
@@ -0,0 +1,126 @@
+package adder
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// memoryTestInput is a root command and one subcommand as an input filesystem
+var memoryTestInput = fstest.MapFS{
+	"app.md":       {Data: []byte("---\ntitle: App\ncommand:\n  name: app\n---\n")},
+	"app/serve.md": {Data: []byte("---\ntitle: Serve\ncommand:\n  name: serve\n---\n")},
+}
+
+func TestMemoryFS(t *testing.T) {
+	m := NewMemoryFS()
+	for name, content := range map[string]string{"a.go": "a", "dir/b.go": "b"} {
+		if err := m.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+	if err := fstest.TestFS(m, "a.go", "dir/b.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.WriteFile("../escape.go", nil, 0644); err == nil {
+		t.Error("WriteFile() expected an error for an invalid name")
+	}
+	if err := m.Remove("dir"); err == nil {
+		t.Error("Remove() expected an error for a directory with files")
+	}
+	if err := m.Remove("dir/b.go"); err != nil {
+		t.Errorf("Remove() error = %v", err)
+	}
+	if err := m.Remove("dir"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove() of an emptied directory error = %v, want fs.ErrNotExist", err)
+	}
+
+	if got := m.Names(); !reflect.DeepEqual(got, []string{"a.go"}) {
+		t.Errorf("Names() = %v, want [a.go]", got)
+	}
+	files := m.Files()
+	files["a.go"][0] = 'x'
+	if content, _ := fs.ReadFile(m, "a.go"); string(content) != "a" {
+		t.Error("Files() should return copies of the content")
+	}
+}
+
+func TestAdder_WithOutputFS(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.BinaryName = "app"
+	config.OutputDir = filepath.Join(t.TempDir(), "generated")
+	output := NewMemoryFS()
+	a := New(config, WithOutputFS(output))
+
+	if err := a.GenerateFromFS(context.Background(), memoryTestInput); err != nil {
+		t.Fatalf("GenerateFromFS() error = %v", err)
+	}
+	want := []string{LockFileName, "app/serve_generated.go", "app_generated.go"}
+	if got := output.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(config.OutputDir); !os.IsNotExist(err) {
+		t.Errorf("nothing should be written to disk, stat error = %v", err)
+	}
+
+	// The lockfile in memory makes the next generation skip both files
+	if err := a.GenerateFromFS(context.Background(), memoryTestInput); err != nil {
+		t.Fatalf("GenerateFromFS() error = %v", err)
+	}
+	if skipped := a.GetStats()["skipped_files"]; skipped != 2 {
+		t.Errorf("skipped_files = %d, want 2", skipped)
+	}
+
+	// Stale files and their directories are removed from memory as well
+	if err := a.GenerateFromFS(context.Background(), fstest.MapFS{"app.md": memoryTestInput["app.md"]}); err != nil {
+		t.Fatalf("GenerateFromFS() error = %v", err)
+	}
+	if removed := a.GetRemovedFiles(); len(removed) != 1 || removed[0] != filepath.Join(config.OutputDir, "app", "serve_generated.go") {
+		t.Errorf("GetRemovedFiles() = %v", removed)
+	}
+	if _, err := fs.Stat(output, "app"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("emptied directory should be gone, stat error = %v", err)
+	}
+}
+
+func TestAdder_RenderCommand(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.BinaryName = "app"
+	cmd, err := NewParser(config).ParseContent(string(memoryTestInput["app/serve.md"].Data), "app/serve.md")
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+
+	content, err := New(config).RenderCommand(cmd)
+	if err != nil {
+		t.Fatalf("RenderCommand() error = %v", err)
+	}
+	for _, want := range []string{generatedHeader, "package app", "func NewServeCommand("} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("rendered command missing %q:\n%s", want, content)
+		}
+	}
+
+	// Commands built in code are validated, so conflicting builder methods are reported
+	conflict := &Command{
+		Title:     "Greet",
+		Name:      "greet",
+		FilePath:  "greet.md",
+		Arguments: []Argument{{Name: "name", Type: TypeString}},
+		Flags:     []Flag{{Name: "name", Type: TypeString}},
+	}
+	if _, err := New(config).RenderCommand(conflict); err == nil || !strings.Contains(err.Error(), "duplicate field name 'Name'") {
+		t.Errorf("RenderCommand() error = %v, want a duplicate field name", err)
+	}
+}
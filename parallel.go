@@ -0,0 +1,55 @@
+package adder
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls fn for the indexes 0..n-1 on a bounded pool of workers
+// Workers stop picking up new indexes once ctx is cancelled or a call fails.
+// The error of the lowest failing index is returned, so failures are reported deterministically.
+func forEachParallel(ctx context.Context, n int, fn func(i int) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := min(runtime.GOMAXPROCS(0), n)
+	indexes := make(chan int)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if errs[i] = fn(i); errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// Without failures, cancel has not run, so this reports cancellation by the caller
+	return ctx.Err()
+}
@@ -0,0 +1,70 @@
+package adder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachParallel(t *testing.T) {
+	var calls atomic.Int64
+	results := make([]int, 100)
+	err := forEachParallel(context.Background(), len(results), func(i int) error {
+		calls.Add(1)
+		results[i] = i * i
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEachParallel() error = %v", err)
+	}
+	if calls.Load() != 100 {
+		t.Errorf("fn called %d times, want 100", calls.Load())
+	}
+	for i, got := range results {
+		if got != i*i {
+			t.Fatalf("results[%d] = %d, want %d", i, got, i*i)
+		}
+	}
+}
+
+func TestForEachParallel_Error(t *testing.T) {
+	err := forEachParallel(context.Background(), 50, func(i int) error {
+		if i%10 == 3 {
+			return fmt.Errorf("item %d failed", i)
+		}
+		return nil
+	})
+	// Later failures may or may not have run, but the first one always wins
+	if err == nil || err.Error() != "item 3 failed" {
+		t.Errorf("forEachParallel() error = %v, want item 3 failed", err)
+	}
+}
+
+func TestForEachParallel_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := forEachParallel(ctx, 10, func(int) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("forEachParallel() error = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Error("fn should not be called after cancellation")
+	}
+}
+
+func TestGenerator_GenerateCancelled(t *testing.T) {
+	config := cleanTestConfig(t, "alpha", "beta")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := New(config).GenerateWithContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateWithContext() error = %v, want context.Canceled", err)
+	}
+}
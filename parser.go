@@ -1,6 +1,7 @@
 package adder
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"path/filepath"
@@ -29,12 +30,29 @@ func NewParser(config *Config) *Parser {
 
 // ParseDirectory parses all markdown files in the input directory
 func (p *Parser) ParseDirectory(fsys fs.FS) ([]*Command, error) {
-	var commands []*Command
+	return p.ParseDirectoryContext(context.Background(), fsys)
+}
+
+// parseJob is a markdown file found while walking the input directory
+type parseJob struct {
+	path        string
+	root        bool   // binary root command or index file of a command group
+	commandPath string // command path of a root command
+	kind        string // used in error messages, e.g. "root command"
+}
+
+// ParseDirectoryContext parses all markdown files in the input directory
+// Files are parsed concurrently; commands are returned in walk order and parsing stops when ctx is cancelled
+func (p *Parser) ParseDirectoryContext(ctx context.Context, fsys fs.FS) ([]*Command, error) {
+	var jobs []parseJob
 
 	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		// Skip directories and non-markdown files
 		if d.IsDir() || !strings.HasSuffix(path, ".md") {
@@ -44,39 +62,23 @@ func (p *Parser) ParseDirectory(fsys fs.FS) ([]*Command, error) {
 		// Check if this is a root command file
 		dir := filepath.Dir(path)
 		filename := filepath.Base(path)
-		
+
 		// Check if this is the binary's root command file (binary_name.md in root directory)
 		if dir == "." && p.config.BinaryName != "" && filename == p.config.BinaryName+".md" {
-			cmd, err := p.ParseFile(fsys, path)
-			if err != nil {
-				return fmt.Errorf("parsing binary root command %s: %w", path, err)
-			}
-			if cmd != nil {
-				cmd.IsRootCommand = true
-				cmd.CommandPath = "" // Root command has no path prefix
-				commands = append(commands, cmd)
-			}
+			jobs = append(jobs, parseJob{path: path, root: true, kind: "binary root command"})
 			return nil
 		}
-		
+
 		// For files in subdirectories, check if it's an index file
 		if dir != "." {
 			dirName := filepath.Base(dir)
 			if p.config.IsIndexFile(filename, dirName) {
 				// This is an index file - process it but mark it as such
-				cmd, err := p.ParseFile(fsys, path)
-				if err != nil {
-					return fmt.Errorf("parsing root command %s: %w", path, err)
-				}
-				if cmd != nil {
-					cmd.IsRootCommand = true
-					cmd.CommandPath = dirName // Set the command path for root commands
-					commands = append(commands, cmd)
-				}
+				jobs = append(jobs, parseJob{path: path, root: true, commandPath: dirName, kind: "root command"})
 				return nil
 			}
 		}
-		
+
 		// Skip other pattern files that are not root commands
 		if filename == "_index.md" || filename == "index.md" {
 			// Only skip if not in root directory and not a configured root command pattern
@@ -85,20 +87,45 @@ func (p *Parser) ParseDirectory(fsys fs.FS) ([]*Command, error) {
 			}
 		}
 
-		cmd, err := p.ParseFile(fsys, path)
+		jobs = append(jobs, parseJob{path: path})
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("walking directory: %w", err)
+	}
+
+	parsed := make([]*Command, len(jobs))
+	err = forEachParallel(ctx, len(jobs), func(i int) error {
+		job := jobs[i]
+		cmd, err := p.ParseFile(fsys, job.path)
 		if err != nil {
-			return fmt.Errorf("parsing %s: %w", path, err)
+			if job.kind != "" {
+				return fmt.Errorf("parsing %s %s: %w", job.kind, job.path, err)
+			}
+			return fmt.Errorf("parsing %s: %w", job.path, err)
+		}
+		if cmd != nil && job.root {
+			cmd.IsRootCommand = true
+			cmd.CommandPath = job.commandPath // The binary root command has no path prefix
 		}
+		parsed[i] = cmd
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	var commands []*Command
+	for _, cmd := range parsed {
 		if cmd != nil {
 			commands = append(commands, cmd)
 		}
+	}
 
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("walking directory: %w", err)
+	// Generated flags must not clash with the persistent flags commands inherit
+	if err := validateCommandTree(BuildCommandTree(p.config.BinaryName, commands)); err != nil {
+		return nil, err
 	}
 
 	return commands, nil
@@ -189,6 +216,29 @@ func (p *Parser) ParseContent(content, filePath string) (*Command, error) {
 		}
 	}
 	
+	// Extract confirmation for destructive commands
+	var destructive bool
+	var confirm string
+	if d, exists := commandMap["destructive"]; exists {
+		if destructiveBool, ok := d.(bool); ok {
+			destructive = destructiveBool
+		}
+	}
+	if c, exists := commandMap["confirm"]; exists {
+		if confirmStr, ok := c.(string); ok {
+			confirm = confirmStr
+			destructive = destructive || confirmStr != ""
+		}
+	}
+	
+	// Extract setup
+	var setup bool
+	if s, exists := commandMap["setup"]; exists {
+		if setupBool, ok := s.(bool); ok {
+			setup = setupBool
+		}
+	}
+	
 	// Parse arguments using our custom logic
 	var arguments []Argument
 	if rawArgs, exists := commandMap["arguments"]; exists {
@@ -259,6 +309,14 @@ func (p *Parser) ParseContent(content, filePath string) (*Command, error) {
 						}
 					}
 					
+					flag.MutuallyExclusive = parseStringList(flagMap["mutually_exclusive"])
+					flag.RequiredTogether = parseStringList(flagMap["required_together"])
+					flag.OneRequired = parseStringList(flagMap["one_required"])
+					flag.Prompt, flag.PromptLabel = parsePrompt(flagMap["prompt"])
+					if sensitive, ok := flagMap["sensitive"].(bool); ok {
+						flag.Sensitive = sensitive
+					}
+					
 					flags = append(flags, flag)
 				}
 			}
@@ -325,20 +383,60 @@ func (p *Parser) ParseContent(content, filePath string) (*Command, error) {
 						}
 					}
 					
+					flag.MutuallyExclusive = parseStringList(flagMap["mutually_exclusive"])
+					flag.RequiredTogether = parseStringList(flagMap["required_together"])
+					flag.OneRequired = parseStringList(flagMap["one_required"])
+					flag.Prompt, flag.PromptLabel = parsePrompt(flagMap["prompt"])
+					if sensitive, ok := flagMap["sensitive"].(bool); ok {
+						flag.Sensitive = sensitive
+					}
+					
 					persistentFlags = append(persistentFlags, flag)
 				}
 			}
 		}
 	}
 
+	// Parse declared output
+	var output *Output
+	if o, exists := commandMap["output"]; exists {
+		parsed, err := p.parseOutput(o, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing output: %w", err)
+		}
+		output = parsed
+	}
+
+	// Parse declared errors ("exit_codes" is accepted as an alias)
+	var cmdErrors []CommandError
+	for _, key := range []string{"errors", "exit_codes"} {
+		e, exists := commandMap[key]
+		if !exists {
+			continue
+		}
+		if cmdErrors != nil {
+			return nil, fmt.Errorf("file %s: errors and exit_codes cannot both be declared", filePath)
+		}
+		parsed, err := p.parseErrors(e, key, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", key, err)
+		}
+		cmdErrors = parsed
+	}
+
 	cmd := &Command{
 		Title:           title,
 		Name:            name,
 		Aliases:         aliases,
 		Hidden:          hidden,
+		Setup:           setup,
+		Destructive:     destructive,
+		Confirm:         confirm,
 		Arguments:       arguments,
 		Flags:           flags,
 		PersistentFlags: persistentFlags,
+		Output:          output,
+		Errors:          cmdErrors,
 		Description:     bodyContent,
 		FilePath:        filePath,
 	}
@@ -351,6 +449,141 @@ func (p *Parser) ParseContent(content, filePath string) (*Command, error) {
 	return cmd, nil
 }
 
+// parseOutput parses the declared output of a command
+func (p *Parser) parseOutput(raw interface{}, filePath string) (*Output, error) {
+	outputMap, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("file %s: output must be an object", filePath)
+	}
+
+	output := &Output{}
+
+	if format, exists := outputMap["format"]; exists {
+		if formatStr, ok := format.(string); ok {
+			output.Format = formatStr
+		}
+	}
+
+	if list, exists := outputMap["list"]; exists {
+		if listBool, ok := list.(bool); ok {
+			output.List = listBool
+		}
+	}
+
+	if f, exists := outputMap["fields"]; exists {
+		fieldsData, ok := f.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("file %s: output fields must be an array", filePath)
+		}
+		for i, fieldData := range fieldsData {
+			fieldMap, ok := fieldData.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("file %s: output field %d: must be an object", filePath, i)
+			}
+
+			field := OutputField{Type: TypeString} // Default type
+
+			if name, exists := fieldMap["name"]; exists {
+				if nameStr, ok := name.(string); ok {
+					field.Name = nameStr
+				} else {
+					return nil, fmt.Errorf("file %s: output field %d: name must be a string", filePath, i)
+				}
+			}
+
+			if desc, exists := fieldMap["description"]; exists {
+				if descStr, ok := desc.(string); ok {
+					field.Description = descStr
+				}
+			}
+
+			if typ, exists := fieldMap["type"]; exists {
+				if typStr, ok := typ.(string); ok {
+					field.Type = typStr
+				}
+			}
+
+			output.Fields = append(output.Fields, field)
+		}
+	}
+
+	output.Columns = parseStringList(outputMap["columns"])
+
+	return output, nil
+}
+
+// parseErrors parses the declared errors of a command
+func (p *Parser) parseErrors(raw interface{}, key, filePath string) ([]CommandError, error) {
+	errorsData, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("file %s: %s must be an array", filePath, key)
+	}
+
+	cmdErrors := []CommandError{}
+	for i, errorData := range errorsData {
+		errorMap, ok := errorData.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("file %s: %s %d: must be an object", filePath, key, i)
+		}
+
+		var cmdErr CommandError
+
+		if name, exists := errorMap["name"]; exists {
+			if nameStr, ok := name.(string); ok {
+				cmdErr.Name = nameStr
+			} else {
+				return nil, fmt.Errorf("file %s: %s %d: name must be a string", filePath, key, i)
+			}
+		}
+
+		if code, exists := errorMap["code"]; exists {
+			if codeInt, ok := code.(int); ok {
+				cmdErr.Code = codeInt
+			} else {
+				return nil, fmt.Errorf("file %s: %s %d: code must be an integer", filePath, key, i)
+			}
+		}
+
+		if desc, exists := errorMap["description"]; exists {
+			if descStr, ok := desc.(string); ok {
+				cmdErr.Description = descStr
+			}
+		}
+
+		cmdErrors = append(cmdErrors, cmdErr)
+	}
+
+	return cmdErrors, nil
+}
+
+// parsePrompt parses a prompt setting, which is either a bool or the prompt label
+func parsePrompt(raw interface{}) (bool, string) {
+	switch v := raw.(type) {
+	case bool:
+		return v, ""
+	case string:
+		return v != "", v
+	default:
+		return false, ""
+	}
+}
+
+// parseStringList converts a YAML string list into a slice, ignoring non-string items
+func parseStringList(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
 // parseArguments handles both string array and object array formats for arguments
 func (p *Parser) parseArguments(rawArgs interface{}, filePath string) ([]Argument, error) {
 	switch args := rawArgs.(type) {
@@ -421,6 +654,11 @@ func (p *Parser) parseArguments(rawArgs interface{}, filePath string) ([]Argumen
 					}
 				}
 
+				argument.Prompt, argument.PromptLabel = parsePrompt(argMap["prompt"])
+				if sensitive, ok := argMap["sensitive"].(bool); ok {
+					argument.Sensitive = sensitive
+				}
+
 				arguments = append(arguments, argument)
 			}
 			return arguments, nil
@@ -495,6 +733,19 @@ func (p *Parser) validateCommand(cmd *Command) error {
 		}
 	}
 
+	// Persistent flags share the builder's With methods with arguments and flags
+	for _, flag := range cmd.PersistentFlags {
+		fieldName := pascalCase(flag.Name)
+		if existing, exists := fieldNames[fieldName]; exists {
+			return fmt.Errorf("file %s: duplicate field name '%s' - persistent flag '%s' conflicts with %s '%s'", 
+				cmd.FilePath, fieldName, flag.Name, existing.fieldType, existing.originalName)
+		}
+		fieldNames[fieldName] = fieldSource{
+			fieldType:    "persistent flag",
+			originalName: flag.Name,
+		}
+	}
+
 	// Run enhanced validation (type consistency, defaults, etc.)
 	return validateCommandConfiguration(cmd, cmd.FilePath)
 }
@@ -518,6 +769,11 @@ func (p *Parser) GetStructName(cmd *Command) string {
 	return pascalCase(p.cleanCommandName(cmd.Name)) + "Request"
 }
 
+// GetResponseName returns the response struct name for the command
+func (p *Parser) GetResponseName(cmd *Command) string {
+	return pascalCase(p.cleanCommandName(cmd.Name)) + "Response"
+}
+
 // GetHandlerName returns the handler interface name for the command
 func (p *Parser) GetHandlerName(cmd *Command) string {
 	return pascalCase(p.cleanCommandName(cmd.Name)) + "Handler"
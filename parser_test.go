@@ -3,6 +3,7 @@ package adder
 import (
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestParser_ParseContent(t *testing.T) {
@@ -359,6 +360,40 @@ command:
 			wantErr:        true,
 			expectedErrMsg: "file test.md: duplicate field name 'UserName' - flag 'user-name' conflicts with argument 'user_name'",
 		},
+		{
+			name: "persistent flag conflicts with argument",
+			content: `---
+title: Persistent Flag Conflict
+command:
+  name: test
+  arguments:
+    - name: name
+      type: string
+  persistent_flags:
+    - name: name
+      type: string
+---`,
+			filePath:       "test.md",
+			wantErr:        true,
+			expectedErrMsg: "file test.md: duplicate field name 'Name' - persistent flag 'name' conflicts with argument 'name'",
+		},
+		{
+			name: "persistent flag conflicts with flag",
+			content: `---
+title: Persistent Flag Conflict
+command:
+  name: test
+  flags:
+    - name: config
+      type: string
+  persistent_flags:
+    - name: config
+      type: string
+---`,
+			filePath:       "test.md",
+			wantErr:        true,
+			expectedErrMsg: "file test.md: duplicate field name 'Config' - persistent flag 'config' conflicts with flag 'config'",
+		},
 		{
 			name: "no duplicates - different names",
 			content: `---
@@ -493,6 +528,147 @@ command:
 			filePath:       "missing-flag.md",
 			expectedErrMsg: "file missing-flag.md: flag 0: name is required",
 		},
+		{
+			name: "flag rule referencing unknown flag",
+			content: `---
+title: Unknown Rule Flag
+command:
+  name: test
+  flags:
+    - name: json
+      type: bool
+      mutually_exclusive:
+        - yaml
+---`,
+			filePath:       "unknown-rule.md",
+			expectedErrMsg: "file unknown-rule.md: flag json: mutually_exclusive references unknown flag 'yaml'",
+		},
+		{
+			name: "flag rule referencing itself",
+			content: `---
+title: Self Rule Flag
+command:
+  name: test
+  flags:
+    - name: user
+      type: string
+      required_together:
+        - user
+---`,
+			filePath:       "self-rule.md",
+			expectedErrMsg: "file self-rule.md: flag user: required_together cannot reference the flag itself",
+		},
+		{
+			name: "output column not a declared field",
+			content: `---
+title: Unknown Column
+command:
+  name: test
+  output:
+    columns: [name, age]
+    fields:
+      - name: name
+---`,
+			filePath:       "unknown-column.md",
+			expectedErrMsg: "file unknown-column.md: output: column 'age' is not a declared field",
+		},
+		{
+			name: "output flag conflicts with generated flag",
+			content: `---
+title: Output Conflict
+command:
+  name: test
+  flags:
+    - name: out
+      shorthand: o
+  output:
+    fields:
+      - name: name
+---`,
+			filePath:       "output-conflict.md",
+			expectedErrMsg: "file output-conflict.md: flag out: conflicts with the generated --output/-o flag",
+		},
+		{
+			name: "sensitive enum flag",
+			content: `---
+title: Sensitive Enum
+command:
+  name: test
+  flags:
+    - name: level
+      enum: [low, high]
+      sensitive: true
+---`,
+			filePath:       "sensitive-enum.md",
+			expectedErrMsg: "file sensitive-enum.md: flag level: sensitive is only supported on string flags without enum",
+		},
+		{
+			name: "yes flag conflicts with destructive command",
+			content: `---
+title: Yes Conflict
+command:
+  name: test
+  destructive: true
+  flags:
+    - name: assume
+      shorthand: "y"
+      type: bool
+---`,
+			filePath:       "yes-conflict.md",
+			expectedErrMsg: "file yes-conflict.md: flag assume: conflicts with the generated --yes/-y flag for destructive commands",
+		},
+		{
+			name: "invalid confirmation template",
+			content: `---
+title: Bad Confirm
+command:
+  name: test
+  confirm: "Delete {{.Arguments.Name?"
+---`,
+			filePath:       "bad-confirm.md",
+			expectedErrMsg: "file bad-confirm.md: confirm: invalid template",
+		},
+		{
+			name: "confirmation template with unknown field",
+			content: `---
+title: Typo Confirm
+command:
+  name: test [name]
+  confirm: "Delete {{.Arguments.Nme}}?"
+  arguments:
+    - name: name
+---`,
+			filePath:       "typo-confirm.md",
+			expectedErrMsg: "file typo-confirm.md: confirm: invalid template: template: confirm:1:19: executing \"confirm\" at <.Arguments.Nme>: map has no entry for key \"Nme\"",
+		},
+		{
+			name: "error code reserved",
+			content: `---
+title: Reserved Code
+command:
+  name: test
+  errors:
+    - name: not-found
+      code: 2
+---`,
+			filePath:       "reserved-code.md",
+			expectedErrMsg: "file reserved-code.md: error not-found: code 2 is out of range (must be 4-125, lower codes are reserved)",
+		},
+		{
+			name: "duplicate error code",
+			content: `---
+title: Duplicate Code
+command:
+  name: test
+  exit_codes:
+    - name: not-found
+      code: 4
+    - name: conflict
+      code: 4
+---`,
+			filePath:       "duplicate-code.md",
+			expectedErrMsg: "file duplicate-code.md: error conflict: code 4 is already used by 'not-found'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -521,3 +697,50 @@ command:
 		})
 	}
 }
+
+func TestParser_InheritedFlagConflicts(t *testing.T) {
+	config := DefaultConfig()
+	config.BinaryName = "app"
+	parser := NewParser(config)
+
+	root := "---\ntitle: App\ncommand:\n  name: app\n  persistent_flags:\n    - name: assume\n      shorthand: \"y\"\n      type: bool\n    - name: out\n      shorthand: o\n---\n"
+
+	tests := []struct {
+		name    string
+		command string
+		wantErr string
+	}{
+		{
+			name:    "destructive command",
+			command: "---\ntitle: Remove\ncommand:\n  name: remove\n  destructive: true\n---\n",
+			wantErr: "file app/remove.md: inherited flag assume: conflicts with the generated --yes/-y flag for destructive commands",
+		},
+		{
+			name:    "command with output",
+			command: "---\ntitle: Remove\ncommand:\n  name: remove\n  output:\n    fields:\n      - name: id\n---\n",
+			wantErr: "file app/remove.md: inherited flag out: conflicts with the generated --output/-o flag for commands with a declared output",
+		},
+		{
+			name:    "other command",
+			command: "---\ntitle: Remove\ncommand:\n  name: remove\n---\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.ParseDirectory(fstest.MapFS{
+				"app.md":        {Data: []byte(root)},
+				"app/remove.md": {Data: []byte(tt.command)},
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseDirectory() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseDirectory() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
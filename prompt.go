@@ -0,0 +1,199 @@
+package adder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Prompter asks for missing values interactively
+// Prompts are written to the output and answers read line by line from the input
+type Prompter struct {
+	in         *bufio.Reader
+	out        io.Writer
+	readSecret func() (string, error)
+}
+
+// NewPrompter creates a prompter reading answers from in and writing prompts to out
+// Sensitive values are read like any other line, which makes it suitable for tests
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	p := &Prompter{in: bufio.NewReader(in), out: out}
+	p.readSecret = p.readLine
+	return p
+}
+
+// NewTerminalPrompter creates a prompter for a terminal, reading sensitive values without echo
+func NewTerminalPrompter(in *os.File, out io.Writer) *Prompter {
+	p := NewPrompter(in, out)
+	p.readSecret = func() (string, error) {
+		secret, err := term.ReadPassword(int(in.Fd()))
+		fmt.Fprintln(out)
+		return string(secret), err
+	}
+	return p
+}
+
+// WithPrompter makes a generated command prompt with p, even when stdin is not a terminal
+func WithPrompter(p *Prompter) CommandOption {
+	return func(o *CommandOptions) {
+		o.Prompter = p
+	}
+}
+
+// PrompterFor returns the prompter for a command, or nil if it cannot prompt
+// Without WithPrompter, commands only prompt when stdin is a terminal; prompts go to stderr.
+// Each terminal prompter buffers its input, so generated commands call it once per execution
+// and prompt and confirm with the same prompter, keeping answers typed ahead.
+func (o *CommandOptions) PrompterFor(cmd *cobra.Command) *Prompter {
+	if o != nil && o.Prompter != nil {
+		return o.Prompter
+	}
+	if f, ok := terminalInput(cmd); ok {
+		return NewTerminalPrompter(f, cmd.ErrOrStderr())
+	}
+	return nil
+}
+
+// canPrompt reports whether PrompterFor returns a prompter for the command, without creating one
+func (o *CommandOptions) canPrompt(cmd *cobra.Command) bool {
+	if o != nil && o.Prompter != nil {
+		return true
+	}
+	_, ok := terminalInput(cmd)
+	return ok
+}
+
+// terminalInput returns the command's input if it is a terminal
+func terminalInput(cmd *cobra.Command) (*os.File, bool) {
+	f, ok := cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, false
+	}
+	return f, true
+}
+
+// ExactArgsOrPrompt requires exactly n positional arguments, or at least min
+// when the command can prompt for the remaining ones
+func (o *CommandOptions) ExactArgsOrPrompt(n, min int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if !o.canPrompt(cmd) {
+			return ExactArgsOrFromFile(n)(cmd, args)
+		}
+		if err := cobra.RangeArgs(min, n)(cmd, args); err != nil {
+			return &UsageError{Err: err}
+		}
+		return nil
+	}
+}
+
+// String asks for a line of text
+func (p *Prompter) String(label string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", label)
+	return p.readLine()
+}
+
+// Password asks for a line of text without echoing it
+func (p *Prompter) Password(label string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", label)
+	return p.readSecret()
+}
+
+// Strings asks for a comma separated list
+func (p *Prompter) Strings(label string) ([]string, error) {
+	fmt.Fprintf(p.out, "%s (comma separated): ", label)
+	line, err := p.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, value := range strings.Split(line, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// Int asks for a whole number, repeating the question until one is given
+func (p *Prompter) Int(label string) (int, error) {
+	for {
+		fmt.Fprintf(p.out, "%s: ", label)
+		line, err := p.readLine()
+		if err != nil {
+			return 0, err
+		}
+		if value, err := strconv.Atoi(line); err == nil {
+			return value, nil
+		}
+		fmt.Fprintln(p.out, "Please enter a number")
+	}
+}
+
+// Confirm asks a yes/no question; an empty answer selects the default
+func (p *Prompter) Confirm(label string, defaultValue bool) (bool, error) {
+	choices := "y/N"
+	if defaultValue {
+		choices = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, choices)
+		line, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer yes or no")
+	}
+}
+
+// Select asks to choose one of the options, by number or by value
+func (p *Prompter) Select(label string, options []string) (string, error) {
+	fmt.Fprintf(p.out, "%s:\n", label)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Choose 1-%d: ", len(options))
+		line, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if i, err := strconv.Atoi(line); err == nil && i >= 1 && i <= len(options) {
+			return options[i-1], nil
+		}
+		for _, option := range options {
+			if line == option {
+				return option, nil
+			}
+		}
+		fmt.Fprintf(p.out, "Please choose %s\n", joinEnumValues(options, "or"))
+	}
+}
+
+// readLine reads a trimmed line, failing when the input ends before an answer
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", NewUsageError("no answer given: input ended")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
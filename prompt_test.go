@@ -0,0 +1,127 @@
+package adder
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPrompter(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		ask     func(p *Prompter) (interface{}, error)
+		want    interface{}
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:    "string",
+			input:   "  my-project \n",
+			ask:     func(p *Prompter) (interface{}, error) { return p.String("Project name") },
+			want:    "my-project",
+			wantOut: "Project name: ",
+		},
+		{
+			name:  "string without trailing newline",
+			input: "last",
+			ask:   func(p *Prompter) (interface{}, error) { return p.String("Name") },
+			want:  "last",
+		},
+		{
+			name:  "password",
+			input: "s3cret\n",
+			ask:   func(p *Prompter) (interface{}, error) { return p.Password("Token") },
+			want:  "s3cret",
+		},
+		{
+			name:  "strings",
+			input: "spanish, french,,german\n",
+			ask:   func(p *Prompter) (interface{}, error) { return p.Strings("Languages") },
+			want:  []string{"spanish", "french", "german"},
+		},
+		{
+			name:    "int retries until valid",
+			input:   "three\n3\n",
+			ask:     func(p *Prompter) (interface{}, error) { return p.Int("Repeat") },
+			want:    3,
+			wantOut: "Please enter a number",
+		},
+		{
+			name:    "confirm default",
+			input:   "\n",
+			ask:     func(p *Prompter) (interface{}, error) { return p.Confirm("Overwrite", true) },
+			want:    true,
+			wantOut: "Overwrite [Y/n]: ",
+		},
+		{
+			name:  "confirm retries until yes or no",
+			input: "maybe\nno\n",
+			ask:   func(p *Prompter) (interface{}, error) { return p.Confirm("Overwrite", true) },
+			want:  false,
+		},
+		{
+			name:    "select by number",
+			input:   "2\n",
+			ask:     func(p *Prompter) (interface{}, error) { return p.Select("Format", []string{"json", "yaml"}) },
+			want:    "yaml",
+			wantOut: "  1) json\n  2) yaml\n",
+		},
+		{
+			name:    "select by value after invalid choice",
+			input:   "xml\njson\n",
+			ask:     func(p *Prompter) (interface{}, error) { return p.Select("Format", []string{"json", "yaml"}) },
+			want:    "json",
+			wantOut: "Please choose json or yaml",
+		},
+		{
+			name:    "input ends",
+			input:   "",
+			ask:     func(p *Prompter) (interface{}, error) { return p.String("Name") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := tt.ask(NewPrompter(strings.NewReader(tt.input), &out))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output %q does not contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestCommandOptions_ExactArgsOrPrompt(t *testing.T) {
+	cmd := &cobra.Command{Use: "create"}
+	cmd.SetIn(strings.NewReader(""))
+
+	// Without a terminal or an injected prompter, all arguments are required
+	if err := NewCommandOptions().ExactArgsOrPrompt(2, 1)(cmd, []string{"a"}); ExitCode(err) != ExitCodeUsage {
+		t.Errorf("expected usage error without prompter, got %v", err)
+	}
+
+	options := NewCommandOptions(WithPrompter(NewPrompter(strings.NewReader(""), &bytes.Buffer{})))
+	if err := options.ExactArgsOrPrompt(2, 1)(cmd, []string{"a"}); err != nil {
+		t.Errorf("unexpected error with prompter: %v", err)
+	}
+	if err := options.ExactArgsOrPrompt(2, 1)(cmd, nil); ExitCode(err) != ExitCodeUsage {
+		t.Errorf("expected usage error for missing non-prompting argument, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package adder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// FromFileFlag is the flag generated commands use to populate requests from a file
+const FromFileFlag = "from-file"
+
+// fromFileUsage is the help text of --from-file
+const fromFileUsage = "Populate the request from a JSON or YAML file (use - for stdin)"
+
+// fromFileValue is the pflag.Value behind --from-file
+// LoadRequestFile records the request keys the file sets, for CheckRequiredFlags
+type fromFileValue struct {
+	path string
+	keys map[string]bool
+}
+
+func (v *fromFileValue) String() string { return v.path }
+func (v *fromFileValue) Type() string   { return "string" }
+
+func (v *fromFileValue) Set(path string) error {
+	v.path = path
+	return nil
+}
+
+// AddFromFileFlag registers the --from-file flag on a generated command
+func AddFromFileFlag(cmd *cobra.Command) {
+	cmd.Flags().Var(&fromFileValue{}, FromFileFlag, fromFileUsage)
+}
+
+// ExactArgsOrFromFile requires exactly n positional arguments, or at most n
+// when the request is populated with --from-file
+// Violations are reported as a *UsageError
+func ExactArgsOrFromFile(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		validate := cobra.ExactArgs(n)
+		if flag := cmd.Flags().Lookup(FromFileFlag); flag != nil && flag.Changed {
+			validate = cobra.RangeArgs(0, n)
+		}
+		if err := validate(cmd, args); err != nil {
+			return &UsageError{Err: err}
+		}
+		return nil
+	}
+}
+
+// LoadRequestFile populates req from the file given with --from-file, if any
+// Returns true if a file was loaded; "-" reads the request from stdin
+// Unreadable or invalid files are reported as a *UsageError
+func LoadRequestFile(cmd *cobra.Command, req Request) (bool, error) {
+	flag := cmd.Flags().Lookup(FromFileFlag)
+	if flag == nil || flag.Value.String() == "" {
+		return false, nil
+	}
+
+	path := flag.Value.String()
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return false, NewUsageError("reading request file %s: %w", path, err)
+	}
+
+	raw, err := decodeRequest(data, req)
+	if err != nil {
+		return false, NewUsageError("loading request file %s: %w", path, err)
+	}
+	if value, ok := flag.Value.(*fromFileValue); ok {
+		value.keys = requestKeys(raw)
+	}
+
+	return true, nil
+}
+
+// DecodeRequest decodes JSON or YAML data into a request using its json tags
+// Fields missing from the data keep their current values; unknown fields are rejected
+func DecodeRequest(data []byte, req interface{}) error {
+	_, err := decodeRequest(data, req)
+	return err
+}
+
+// decodeRequest decodes data into req and returns the decoded data as JSON-compatible values
+func decodeRequest(data []byte, req interface{}) (interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing request: %w", err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	// Re-encode as JSON so the request's json tags drive decoding
+	raw = normalizeYAML(raw)
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("converting request: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return nil, fmt.Errorf("decoding request: %w", err)
+	}
+
+	return raw, nil
+}
+
+// requestKeys returns the keys set in each section of a decoded request, e.g. flags.dryRun
+func requestKeys(raw interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	sections, _ := raw.(map[string]interface{})
+	for section, value := range sections {
+		fields, _ := value.(map[string]interface{})
+		for key := range fields {
+			keys[section+"."+key] = true
+		}
+	}
+	return keys
+}
+
+// RequiredFlag is a required flag of a generated command and the key of its
+// request field in a request file, e.g. flags.dryRun
+type RequiredFlag struct {
+	Name string
+	Key  string
+}
+
+// CheckRequiredFlags reports the required flags that were neither given nor set by the --from-file file
+// Generated commands check required flags with it instead of cobra, which would reject them before
+// the file is read. Missing flags are reported as a *UsageError, like cobra's
+func CheckRequiredFlags(cmd *cobra.Command, flags ...RequiredFlag) error {
+	var keys map[string]bool
+	if flag := cmd.Flags().Lookup(FromFileFlag); flag != nil {
+		if value, ok := flag.Value.(*fromFileValue); ok {
+			keys = value.keys
+		}
+	}
+
+	var missing []string
+	for _, required := range flags {
+		if flag := cmd.Flags().Lookup(required.Name); flag != nil && flag.Changed {
+			continue
+		}
+		if keys[required.Key] {
+			continue
+		}
+		missing = append(missing, strconv.Quote(required.Name))
+	}
+
+	if len(missing) > 0 {
+		return NewUsageError("required flag(s) %s not set", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// normalizeYAML converts yaml.v2 maps into JSON-compatible maps
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYAML(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
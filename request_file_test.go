@@ -0,0 +1,209 @@
+package adder
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type fileTestRequest struct {
+	Arguments struct {
+		Name string `json:"name"`
+	} `json:"arguments"`
+	Flags struct {
+		Repeat    int      `json:"repeat"`
+		Prefix    string   `json:"prefix"`
+		Languages []string `json:"languages"`
+	} `json:"flags"`
+	RawArguments []string `json:"raw_arguments"`
+}
+
+func (r *fileTestRequest) GetRawArguments() []string { return r.RawArguments }
+
+func TestDecodeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "yaml",
+			data: "arguments:\n  name: Alice\nflags:\n  repeat: 3\n  languages: [french, german]\n",
+		},
+		{
+			name: "json",
+			data: `{"arguments": {"name": "Alice"}, "flags": {"repeat": 3, "languages": ["french", "german"]}}`,
+		},
+		{
+			name:    "unknown field",
+			data:    "flags:\n  repat: 3\n",
+			wantErr: `unknown field "repat"`,
+		},
+		{
+			name:    "wrong type",
+			data:    "flags:\n  repeat: many\n",
+			wantErr: "cannot unmarshal string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &fileTestRequest{}
+			req.Flags.Prefix = "Hello"
+
+			err := DecodeRequest([]byte(tt.data), req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DecodeRequest() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeRequest() unexpected error: %v", err)
+			}
+
+			if req.Arguments.Name != "Alice" || req.Flags.Repeat != 3 || len(req.Flags.Languages) != 2 {
+				t.Errorf("DecodeRequest() decoded %+v", req)
+			}
+			if req.Flags.Prefix != "Hello" {
+				t.Errorf("DecodeRequest() overwrote field missing from data: prefix = %q", req.Flags.Prefix)
+			}
+		})
+	}
+}
+
+func TestLoadRequestFile(t *testing.T) {
+	newCommand := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+		cmd.Flags().String("prefix", "", "Prefix")
+		AddFromFileFlag(cmd)
+		return cmd
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		loaded, err := LoadRequestFile(newCommand(), &fileTestRequest{})
+		if err != nil || loaded {
+			t.Errorf("LoadRequestFile() = %v, %v; want false, nil", loaded, err)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "req.yaml")
+		if err := os.WriteFile(path, []byte("flags:\n  prefix: Hi\n"), 0644); err != nil {
+			t.Fatalf("Failed to write request file: %v", err)
+		}
+
+		cmd := newCommand()
+		cmd.SetArgs([]string{"--from-file", path})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		req := &fileTestRequest{}
+		loaded, err := LoadRequestFile(cmd, req)
+		if err != nil || !loaded {
+			t.Fatalf("LoadRequestFile() = %v, %v; want true, nil", loaded, err)
+		}
+		if req.Flags.Prefix != "Hi" {
+			t.Errorf("prefix = %q, want %q", req.Flags.Prefix, "Hi")
+		}
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		cmd := newCommand()
+		cmd.SetArgs([]string{"--from-file", "-"})
+		cmd.SetIn(strings.NewReader(`{"arguments": {"name": "Bob"}}`))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+
+		req := &fileTestRequest{}
+		if _, err := LoadRequestFile(cmd, req); err != nil {
+			t.Fatalf("LoadRequestFile() failed: %v", err)
+		}
+		if req.Arguments.Name != "Bob" {
+			t.Errorf("name = %q, want %q", req.Arguments.Name, "Bob")
+		}
+	})
+}
+
+func TestCheckRequiredFlags(t *testing.T) {
+	required := []RequiredFlag{{Name: "prefix", Key: "flags.prefix"}, {Name: "repeat", Key: "flags.repeat"}}
+
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		wantErr string
+	}{
+		{name: "flags given", args: []string{"--prefix", "Hi", "--repeat", "0"}},
+		{name: "missing", args: []string{"--prefix", "Hi"}, wantErr: `required flag(s) "repeat" not set`},
+		{name: "file sets int flag", file: "flags:\n  repeat: 0\n", args: []string{"--prefix", "Hi"}},
+		{name: "file sets all flags", file: "flags:\n  prefix: Hi\n  repeat: 2\n"},
+		{name: "file sets other section", file: "arguments:\n  name: Bob\n", wantErr: `required flag(s) "prefix", "repeat" not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checkErr error
+			cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, _ []string) error {
+				if _, err := LoadRequestFile(cmd, &fileTestRequest{}); err != nil {
+					return err
+				}
+				checkErr = CheckRequiredFlags(cmd, required...)
+				return nil
+			}}
+			cmd.Flags().String("prefix", "", "Prefix")
+			cmd.Flags().Int("repeat", 1, "Repeat")
+			AddFromFileFlag(cmd)
+
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "req.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0644); err != nil {
+					t.Fatalf("Failed to write request file: %v", err)
+				}
+				args = append(args, "--from-file", path)
+			}
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+
+			if tt.wantErr == "" {
+				if checkErr != nil {
+					t.Errorf("CheckRequiredFlags() unexpected error: %v", checkErr)
+				}
+				return
+			}
+			var usageErr *UsageError
+			if !errors.As(checkErr, &usageErr) || checkErr.Error() != tt.wantErr {
+				t.Errorf("CheckRequiredFlags() = %v, want usage error %q", checkErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExactArgsOrFromFile(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	AddFromFileFlag(cmd)
+	args := ExactArgsOrFromFile(1)
+
+	if err := args(cmd, nil); err == nil {
+		t.Errorf("expected error for missing argument without --from-file")
+	}
+
+	if err := cmd.Flags().Set(FromFileFlag, "req.yaml"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	if err := args(cmd, nil); err != nil {
+		t.Errorf("unexpected error with --from-file: %v", err)
+	}
+	if err := args(cmd, []string{"a", "b"}); err == nil {
+		t.Errorf("expected error for too many arguments with --from-file")
+	}
+}
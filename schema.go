@@ -32,6 +32,9 @@ type CommandDefinition struct {
 	// Behavior properties
 	Hidden     bool `json:"hidden,omitempty" jsonschema:"title=Hidden Command,description=Hide this command from help output"`
 	Deprecated string `json:"deprecated,omitempty" jsonschema:"title=Deprecated Warning,description=Mark command as deprecated with custom message"`
+	Setup      bool   `json:"setup,omitempty" jsonschema:"title=Setup Hook,description=Generate a setup hook that runs before this command and its subcommands (typically a command group index file)"`
+	Destructive bool  `json:"destructive,omitempty" jsonschema:"title=Destructive,description=Ask for confirmation before running unless --yes is given"`
+	Confirm    string `json:"confirm,omitempty" jsonschema:"title=Confirmation Message,description=Confirmation question rendered with the request (e.g. Delete {{.Arguments.Name}}?); implies destructive"`
 	
 	// Argument validation
 	Arguments []ArgumentDefinition `json:"arguments,omitempty" jsonschema:"title=Command Arguments,description=Positional arguments for the command"`
@@ -40,6 +43,13 @@ type CommandDefinition struct {
 	Flags           []FlagDefinition `json:"flags,omitempty" jsonschema:"title=Command Flags,description=Command-specific flags"`
 	PersistentFlags []FlagDefinition `json:"persistent_flags,omitempty" jsonschema:"title=Persistent Flags,description=Flags inherited by subcommands"`
 	
+	// Typed result
+	Output *OutputDefinition `json:"output,omitempty" jsonschema:"title=Command Output,description=Typed result returned by the handler and rendered with the generated --output flag"`
+	
+	// Declared errors
+	Errors []ErrorDefinition `json:"errors,omitempty" jsonschema:"title=Errors,description=Errors the command can return with their exit codes (exit_codes is accepted as an alias)"`
+	ExitCodes []ErrorDefinition `json:"exit_codes,omitempty" jsonschema:"title=Exit Codes,description=Alias of errors (only one of them can be declared)"`
+	
 	// Advanced Cobra features
 	GroupID               string            `json:"group_id,omitempty" jsonschema:"title=Command Group,description=Group ID for organizing subcommands"`
 	SuggestFor            []string          `json:"suggest_for,omitempty" jsonschema:"title=Suggest For,description=Commands this should be suggested for"`
@@ -67,6 +77,10 @@ type ArgumentDefinition struct {
 	Description string `json:"description,omitempty" jsonschema:"title=Description,description=Description of the argument"`
 	Required    bool   `json:"required,omitempty" jsonschema:"title=Required,description=Whether this argument is required"`
 	Type        string `json:"type,omitempty" jsonschema:"title=Argument Type,description=Type of the argument,enum=string;int;bool,default=string"`
+	
+	// Interactive prompting
+	Prompt    interface{} `json:"prompt,omitempty" jsonschema:"title=Prompt,description=Prompt for the argument when missing and stdin is a terminal (true or the prompt label)"`
+	Sensitive bool        `json:"sensitive,omitempty" jsonschema:"title=Sensitive,description=Read the prompted value with hidden input"`
 }
 
 // FlagDefinition defines a command flag with comprehensive Cobra support
@@ -82,6 +96,10 @@ type FlagDefinition struct {
 	// Validation
 	Enum []string `json:"enum,omitempty" jsonschema:"title=Enum Values,description=Valid values for string flags"`
 	
+	// Interactive prompting
+	Prompt    interface{} `json:"prompt,omitempty" jsonschema:"title=Prompt,description=Prompt for the flag when missing and stdin is a terminal (true or the prompt label)"`
+	Sensitive bool        `json:"sensitive,omitempty" jsonschema:"title=Sensitive,description=Read the prompted value with hidden input"`
+	
 	// Advanced flag features
 	Hidden     bool `json:"hidden,omitempty" jsonschema:"title=Hidden Flag,description=Hide this flag from help output"`
 	Deprecated string `json:"deprecated,omitempty" jsonschema:"title=Deprecated Warning,description=Mark flag as deprecated with custom message"`
@@ -101,6 +119,28 @@ type FlagDefinition struct {
 	OneRequired       []string `json:"one_required,omitempty" jsonschema:"title=One Required,description=At least one of these flags must be provided"`
 }
 
+// OutputDefinition defines the typed result of a command
+type OutputDefinition struct {
+	Format  string                  `json:"format,omitempty" jsonschema:"title=Default Format,description=Default output format,enum=json;yaml;table;ndjson,default=table"`
+	List    bool                    `json:"list,omitempty" jsonschema:"title=List,description=Handler returns a list of results"`
+	Fields  []OutputFieldDefinition `json:"fields" jsonschema:"title=Fields,description=Fields of the result,required"`
+	Columns []string                `json:"columns,omitempty" jsonschema:"title=Table Columns,description=Fields shown as table columns (defaults to all fields)"`
+}
+
+// OutputFieldDefinition defines a field of a command result
+type OutputFieldDefinition struct {
+	Name        string `json:"name" jsonschema:"title=Field Name,description=Name of the field,required"`
+	Description string `json:"description,omitempty" jsonschema:"title=Description,description=Description of the field"`
+	Type        string `json:"type,omitempty" jsonschema:"title=Field Type,description=Type of the field,enum=string;bool;int;stringArray,default=string"`
+}
+
+// ErrorDefinition defines a declared error of a command
+type ErrorDefinition struct {
+	Name        string `json:"name" jsonschema:"title=Error Name,description=Name of the error (used for the generated constructor),required"`
+	Code        int    `json:"code" jsonschema:"title=Exit Code,description=Process exit code (0-3 are reserved),minimum=4,maximum=125,required"`
+	Description string `json:"description,omitempty" jsonschema:"title=Description,description=When the error is returned (shown in help)"`
+}
+
 // SupportedTypes defines the types supported by adder
 var SupportedTypes = struct {
 	String      string
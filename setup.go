@@ -0,0 +1,107 @@
+package adder
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// SetupFunc prepares the environment for a command (e.g. loading config or authenticating)
+// The returned teardown, if not nil, runs after the handler
+type SetupFunc func(cmd *cobra.Command) (teardown func() error, err error)
+
+var (
+	setupMu sync.RWMutex
+	setups  = make(map[*cobra.Command][]SetupFunc)
+)
+
+// RegisterSetup registers a setup hook for a command and all of its subcommands
+// Unlike cobra's PersistentPreRunE, hooks of every ancestor run, not just the nearest
+func RegisterSetup(cmd *cobra.Command, setup SetupFunc) {
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	setups[cmd] = append(setups[cmd], setup)
+}
+
+// UnregisterSetup removes the setup hooks of cmd and all of its subcommands
+// Hooks are registered globally, so tests building command trees call it to not leak hooks,
+// or the trees they reference, into other tests:
+//
+//	t.Cleanup(func() { adder.UnregisterSetup(root) })
+func UnregisterSetup(cmd *cobra.Command) {
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	unregisterSetup(cmd)
+}
+
+func unregisterSetup(cmd *cobra.Command) {
+	delete(setups, cmd)
+	for _, sub := range cmd.Commands() {
+		unregisterSetup(sub)
+	}
+}
+
+// RunSetup runs the setup hooks of cmd's ancestors and cmd itself, from the root down
+// The returned teardown runs their teardowns in reverse order; if a setup fails,
+// the teardowns of the hooks that already ran are called before returning
+func RunSetup(cmd *cobra.Command) (teardown func() error, err error) {
+	var path []*cobra.Command
+	for c := cmd; c != nil; c = c.Parent() {
+		path = append([]*cobra.Command{c}, path...)
+	}
+
+	var teardowns []func() error
+	teardown = func() error {
+		var errs []error
+		for i := len(teardowns) - 1; i >= 0; i-- {
+			if err := teardowns[i](); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	setupMu.RLock()
+	var hooks []SetupFunc
+	for _, c := range path {
+		hooks = append(hooks, setups[c]...)
+	}
+	setupMu.RUnlock()
+
+	for _, setup := range hooks {
+		td, err := setup(cmd)
+		if err != nil {
+			return nil, joinErrors(err, teardown())
+		}
+		if td != nil {
+			teardowns = append(teardowns, td)
+		}
+	}
+
+	return teardown, nil
+}
+
+// runSetup is the outermost layer of every handler chain
+func runSetup(next HandlerFunc) HandlerFunc {
+	return func(cmd *cobra.Command, req Request) (err error) {
+		teardown, err := RunSetup(cmd)
+		if err != nil {
+			return err
+		}
+		defer func() { err = joinErrors(err, teardown()) }()
+
+		return next(cmd, req)
+	}
+}
+
+// joinErrors joins two errors, returning either one unchanged when the other is nil
+func joinErrors(err, other error) error {
+	if other == nil {
+		return err
+	}
+	if err == nil {
+		return other
+	}
+	return errors.Join(err, other)
+}
@@ -0,0 +1,126 @@
+package adder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunSetup(t *testing.T) {
+	var calls []string
+	hook := func(name string, setupErr error) SetupFunc {
+		return func(cmd *cobra.Command) (func() error, error) {
+			calls = append(calls, "setup "+name)
+			if setupErr != nil {
+				return nil, setupErr
+			}
+			return func() error {
+				calls = append(calls, "teardown "+name)
+				return nil
+			}, nil
+		}
+	}
+
+	newTree := func(leafErr error) *cobra.Command {
+		root := &cobra.Command{Use: "app"}
+		group := &cobra.Command{Use: "profile"}
+		leaf := &cobra.Command{Use: "create"}
+		root.AddCommand(group)
+		group.AddCommand(leaf)
+
+		RegisterSetup(root, hook("root", nil))
+		RegisterSetup(group, hook("profile", nil))
+		RegisterSetup(leaf, hook("create", leafErr))
+		t.Cleanup(func() { UnregisterSetup(root) })
+		return leaf
+	}
+
+	t.Run("runs ancestors first and tears down in reverse", func(t *testing.T) {
+		calls = nil
+		teardown, err := RunSetup(newTree(nil))
+		if err != nil {
+			t.Fatalf("RunSetup() failed: %v", err)
+		}
+		calls = append(calls, "handler")
+		if err := teardown(); err != nil {
+			t.Fatalf("teardown failed: %v", err)
+		}
+
+		want := "setup root,setup profile,setup create,handler,teardown create,teardown profile,teardown root"
+		if got := strings.Join(calls, ","); got != want {
+			t.Errorf("calls = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("failed setup tears down completed hooks", func(t *testing.T) {
+		calls = nil
+		_, err := RunSetup(newTree(errors.New("not logged in")))
+		if err == nil || err.Error() != "not logged in" {
+			t.Fatalf("RunSetup() error = %v, want setup error", err)
+		}
+
+		want := "setup root,setup profile,setup create,teardown profile,teardown root"
+		if got := strings.Join(calls, ","); got != want {
+			t.Errorf("calls = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestCommandOptions_WrapRunsSetup(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	leaf := &cobra.Command{Use: "run"}
+	root.AddCommand(leaf)
+
+	var calls []string
+	RegisterSetup(root, func(cmd *cobra.Command) (func() error, error) {
+		calls = append(calls, "setup")
+		return func() error {
+			calls = append(calls, "teardown")
+			return errors.New("close failed")
+		}, nil
+	})
+
+	t.Cleanup(func() { UnregisterSetup(root) })
+
+	handler := NewCommandOptions().Wrap(leaf, func(cmd *cobra.Command, req Request) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	err := handler(leaf, nil)
+	if err == nil || err.Error() != "close failed" {
+		t.Errorf("error = %v, want teardown error", err)
+	}
+	if got := strings.Join(calls, ","); got != "setup,handler,teardown" {
+		t.Errorf("calls = %s, want setup,handler,teardown", got)
+	}
+}
+
+func TestUnregisterSetup(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	leaf := &cobra.Command{Use: "run"}
+	root.AddCommand(leaf)
+
+	called := 0
+	hook := func(cmd *cobra.Command) (func() error, error) {
+		called++
+		return nil, nil
+	}
+	RegisterSetup(root, hook)
+	RegisterSetup(leaf, hook)
+
+	UnregisterSetup(root)
+	if _, err := RunSetup(leaf); err != nil {
+		t.Fatalf("RunSetup() failed: %v", err)
+	}
+	if called != 0 {
+		t.Errorf("unregistered hooks ran %d times", called)
+	}
+	setupMu.RLock()
+	defer setupMu.RUnlock()
+	if len(setups[root]) != 0 || len(setups[leaf]) != 0 {
+		t.Error("hooks of the command and its subcommands should be removed")
+	}
+}
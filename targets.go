@@ -0,0 +1,108 @@
+package adder
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// parseTargets reads the targets list of a config file
+// Each target starts from the shared settings of the file and overrides the ones it sets
+func parseTargets(data []byte, shared *Config, configPath string) ([]*Config, error) {
+	var raw struct {
+		Targets []yaml.MapSlice `yaml:"targets"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	targets := make([]*Config, 0, len(raw.Targets))
+	names := make(map[string]bool)
+	for i, item := range raw.Targets {
+		target := shared.Clone()
+		target.Name = ""
+		target.Targets = nil
+
+		// Round trip the entry, so only the keys it sets replace the shared settings
+		entry, err := yaml.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("target %d: %w", i+1, err)
+		}
+		if err := yaml.Unmarshal(entry, target); err != nil {
+			return nil, fmt.Errorf("target %d: %w", i+1, err)
+		}
+
+		switch {
+		case target.Name == "":
+			return nil, fmt.Errorf("target %d: name is required", i+1)
+		case names[target.Name]:
+			return nil, fmt.Errorf("target %s is defined more than once", target.Name)
+		case len(target.Targets) > 0:
+			return nil, fmt.Errorf("target %s: targets cannot be nested", target.Name)
+		case target.BinaryName == "":
+			return nil, fmt.Errorf("target %s: binary_name is required", target.Name)
+		}
+		names[target.Name] = true
+		target.setFileSources(item, fmt.Sprintf("%s (target %s)", configPath, target.Name))
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// SelectTargets returns the configs to generate for the target name
+// An empty name selects every target. A config without targets is its own single target.
+func (c *Config) SelectTargets(name string) ([]*Config, error) {
+	if len(c.Targets) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("target %s not found: the config has no targets", name)
+		}
+		return []*Config{c}, nil
+	}
+	if name == "" {
+		return c.Targets, nil
+	}
+
+	names := make([]string, len(c.Targets))
+	for i, target := range c.Targets {
+		if target.Name == name {
+			return []*Config{target}, nil
+		}
+		names[i] = target.Name
+	}
+	return nil, fmt.Errorf("target %s not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// CheckTargetOutputs returns an error when two configs write to the same or nested output directories
+// Generating one target would otherwise overwrite, or remove as stale, the files of another
+func CheckTargetOutputs(configs []*Config) error {
+	type output struct {
+		name string
+		dir  string
+	}
+	outputs := make([]output, len(configs))
+	for i, config := range configs {
+		dir, err := filepath.Abs(config.OutputDir)
+		if err != nil {
+			return err
+		}
+		name := config.Name
+		if name == "" {
+			name = config.BinaryName
+		}
+		outputs[i] = output{name: name, dir: dir}
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].dir < outputs[j].dir })
+
+	for i := range outputs {
+		for _, other := range outputs[i+1:] {
+			if other.dir == outputs[i].dir || strings.HasPrefix(other.dir, outputs[i].dir+string(filepath.Separator)) {
+				return fmt.Errorf("targets %s and %s have overlapping outputs: %s and %s", outputs[i].name, other.name, outputs[i].dir, other.dir)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package adder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const targetsTestConfig = `package_strategy: single
+validation:
+  strict: true
+targets:
+  - name: server
+    binary_name: serverctl
+    input: cmd/server/docs
+    output: cmd/server/generated
+  - name: agent
+    binary_name: agent
+    input: cmd/agent/docs
+    output: cmd/agent/generated
+    package: agentcli
+    validation:
+      strict: false
+`
+
+func TestLoadConfigFile_Targets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".adder.yaml")
+	writeTestFile(t, path, targetsTestConfig)
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if len(config.Targets) != 2 {
+		t.Fatalf("Targets = %d, want 2", len(config.Targets))
+	}
+
+	server, agent := config.Targets[0], config.Targets[1]
+	if server.Name != "server" || server.BinaryName != "serverctl" || server.OutputDir != filepath.Join(dir, "cmd", "server", "generated") {
+		t.Errorf("server target = %+v", server)
+	}
+	// Shared settings apply unless a target sets them, including to false
+	if server.PackageStrategy != "single" || agent.PackageStrategy != "single" {
+		t.Errorf("targets should share package_strategy: %q, %q", server.PackageStrategy, agent.PackageStrategy)
+	}
+	if !server.Validation.Strict || agent.Validation.Strict {
+		t.Errorf("strict = %v, %v; want true, false", server.Validation.Strict, agent.Validation.Strict)
+	}
+	if server.Package != "generated" || agent.Package != "agentcli" {
+		t.Errorf("package = %q, %q", server.Package, agent.Package)
+	}
+
+	selected, err := config.SelectTargets("agent")
+	if err != nil || len(selected) != 1 || selected[0] != agent {
+		t.Errorf("SelectTargets(agent) = %v, %v", selected, err)
+	}
+	if selected, err := config.SelectTargets(""); err != nil || len(selected) != 2 {
+		t.Errorf("SelectTargets(\"\") = %v, %v; want every target", selected, err)
+	}
+	if _, err := config.SelectTargets("missing"); err == nil || !strings.Contains(err.Error(), "available: server, agent") {
+		t.Errorf("SelectTargets(missing) error = %v", err)
+	}
+}
+
+func TestLoadConfigFile_InvalidTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "missing name",
+			config:  "targets:\n  - binary_name: app\n",
+			wantErr: "target 1: name is required",
+		},
+		{
+			name:    "duplicate name",
+			config:  "targets:\n  - name: app\n    binary_name: app\n    output: a\n  - name: app\n    binary_name: app\n    output: b\n",
+			wantErr: "target app is defined more than once",
+		},
+		{
+			name:    "missing binary name",
+			config:  "targets:\n  - name: app\n",
+			wantErr: "target app: binary_name is required",
+		},
+		{
+			name:    "nested targets",
+			config:  "targets:\n  - name: app\n    binary_name: app\n    targets:\n      - name: inner\n",
+			wantErr: "targets cannot be nested",
+		},
+		{
+			name:    "same output",
+			config:  "targets:\n  - name: a\n    binary_name: a\n  - name: b\n    binary_name: b\n",
+			wantErr: "targets a and b have overlapping outputs",
+		},
+		{
+			name:    "nested output",
+			config:  "targets:\n  - name: a\n    binary_name: a\n    output: gen\n  - name: b\n    binary_name: b\n    output: gen/b\n",
+			wantErr: "targets a and b have overlapping outputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".adder.yaml")
+			writeTestFile(t, path, tt.config)
+
+			_, err := LoadConfigFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_SelectTargets_Single(t *testing.T) {
+	config := DefaultConfig()
+	if selected, err := config.SelectTargets(""); err != nil || len(selected) != 1 || selected[0] != config {
+		t.Errorf("SelectTargets(\"\") = %v, %v; want the config itself", selected, err)
+	}
+	if _, err := config.SelectTargets("app"); err == nil {
+		t.Error("SelectTargets(app) expected an error without targets")
+	}
+}
@@ -0,0 +1,110 @@
+package adder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Names of the built-in templates
+const (
+	packageTemplateName = "package" // File header, package clause and imports
+	commandTemplateName = "command" // Code for a single command
+)
+
+// templateBlocks are the empty blocks of the built-in templates that custom templates can define
+var templateBlocks = []string{
+	"header",      // Text before the generated code header, e.g. a license
+	"imports",     // Extra import specs
+	"constructor", // Statements run on the new *cobra.Command before the constructor returns it
+}
+
+// templateSet is the compiled set of templates a generator renders with
+type templateSet struct {
+	root   *template.Template
+	extras []string // Custom templates rendered after every command, in name order
+	hash   string   // Hash of every template text and of the template functions, recorded in the lockfile
+}
+
+// loadTemplateSet compiles the built-in templates and the *.tmpl files of dir, if set
+// A file named package.tmpl or command.tmpl replaces that built-in template, a file named
+// after a block defines it, and every other file is rendered after each command.
+// Files can also {{define}} blocks and helpers used by other templates.
+func loadTemplateSet(dir string) (*templateSet, error) {
+	funcs := TemplateFunctions()
+	set := &templateSet{root: template.New("adder").Funcs(funcs)}
+	hash := sha256.New()
+
+	// Functions are code, so their versions stand in for their text
+	_, _ = fmt.Fprintf(hash, "functions=%s\x00", templateFunctionsFingerprint())
+
+	parse := func(name, text string) error {
+		_, _ = io.WriteString(hash, name+"\x00"+text+"\x00")
+		_, err := set.root.New(name).Parse(text)
+		return err
+	}
+	if err := parse(packageTemplateName, Templates.Package); err != nil {
+		return nil, fmt.Errorf("parsing package template: %w", err)
+	}
+	if err := parse(commandTemplateName, Templates.Command); err != nil {
+		return nil, fmt.Errorf("parsing command template: %w", err)
+	}
+
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("templates directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("templates directory %s is not a directory", dir)
+		}
+
+		// Glob returns the files sorted, so definitions and extras have a stable order
+		paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("listing templates: %w", err)
+		}
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("reading template: %w", err)
+			}
+			name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+			if err := parse(name, string(content)); err != nil {
+				return nil, fmt.Errorf("parsing template %s: %w", path, err)
+			}
+			if !isBuiltinTemplate(name) {
+				set.extras = append(set.extras, name)
+			}
+		}
+	}
+
+	set.hash = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	return set, nil
+}
+
+// isBuiltinTemplate reports whether name is a built-in template or one of its blocks
+func isBuiltinTemplate(name string) bool {
+	if name == packageTemplateName || name == commandTemplateName {
+		return true
+	}
+	for _, block := range templateBlocks {
+		if name == block {
+			return true
+		}
+	}
+	return false
+}
+
+// templates returns the compiled templates of the generator, loading them on first use
+func (g *Generator) templates() (*templateSet, error) {
+	g.templatesOnce.Do(func() {
+		g.templateSet, g.templatesErr = loadTemplateSet(g.config.TemplatesDir)
+	})
+	return g.templateSet, g.templatesErr
+}
@@ -0,0 +1,182 @@
+package adder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+const templateTestMarkdown = `---
+title: Greet
+command:
+  name: greet
+---
+`
+
+// generateWithTemplates renders the greet command with the given custom template files
+func generateWithTemplates(t *testing.T, files map[string]string) (string, error) {
+	t.Helper()
+	config := DefaultConfig()
+	config.TemplatesDir = t.TempDir()
+	for name, content := range files {
+		writeTestFile(t, filepath.Join(config.TemplatesDir, name), content)
+	}
+
+	cmd, err := NewParser(config).ParseContent(templateTestMarkdown, "hello/greet.md")
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+	return NewGenerator(config).generateFileContent([]*Command{cmd})
+}
+
+func TestGenerator_TemplateBlocks(t *testing.T) {
+	content, err := generateWithTemplates(t, map[string]string{
+		"header.tmpl":      "{{define \"header\"}}// Copyright Example Authors\n\n{{end}}",
+		"imports.tmpl":     "{{define \"imports\"}}\n\t\"log/slog\"{{end}}",
+		"constructor.tmpl": "{{define \"constructor\"}}\n\tslog.Debug(\"constructed\", \"command\", cmd.Name()){{end}}",
+	})
+	if err != nil {
+		t.Fatalf("generateFileContent() error = %v", err)
+	}
+
+	if !strings.HasPrefix(content, "// Copyright Example Authors\n\n"+generatedHeader) {
+		t.Errorf("header block should come before the generated header:\n%s", content)
+	}
+	for _, want := range []string{`"log/slog"`, `slog.Debug("constructed", "command", cmd.Name())`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code missing %q:\n%s", want, content)
+		}
+	}
+
+	// Files with a license header are still recognised as generated
+	output := NewMemoryFS()
+	if err := output.WriteFile("greet_generated.go", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if generated, err := isGeneratedFile(output, "greet_generated.go"); err != nil || !generated {
+		t.Errorf("isGeneratedFile() = %v, %v; want true", generated, err)
+	}
+}
+
+func TestGenerator_ExtraTemplate(t *testing.T) {
+	content, err := generateWithTemplates(t, map[string]string{
+		"request_extra.tmpl": "// Validate checks the {{.Command.Name}} request\nfunc (r *{{.StructName}}) Validate() error { return nil }\n",
+		"helpers.tmpl":       "{{define \"unused\"}}ignored{{end}}",
+	})
+	if err != nil {
+		t.Fatalf("generateFileContent() error = %v", err)
+	}
+
+	if !strings.Contains(content, "func (r *GreetRequest) Validate() error") {
+		t.Errorf("extra template was not rendered:\n%s", content)
+	}
+	if strings.Contains(content, "ignored") {
+		t.Errorf("templates that only define blocks should add nothing:\n%s", content)
+	}
+}
+
+func TestGenerator_ReplaceBuiltinTemplate(t *testing.T) {
+	content, err := generateWithTemplates(t, map[string]string{
+		"command.tmpl": "\n// {{.StructName}} is replaced\ntype {{.StructName}} struct{}\n",
+	})
+	if err != nil {
+		t.Fatalf("generateFileContent() error = %v", err)
+	}
+	if !strings.Contains(content, "// GreetRequest is replaced") || strings.Contains(content, "NewGreetCommand") {
+		t.Errorf("command.tmpl should replace the built-in command template:\n%s", content)
+	}
+}
+
+func TestRegisterTemplateFunctions(t *testing.T) {
+	before, err := loadTemplateSet("")
+	if err != nil {
+		t.Fatalf("loadTemplateSet() error = %v", err)
+	}
+	RegisterTemplateFunctions(template.FuncMap{
+		"license": func() string { return "// SPDX-License-Identifier: MIT\n\n" },
+	})
+	defer func() {
+		templateFuncsMu.Lock()
+		delete(templateFuncs, "license")
+		delete(templateFuncsVersions, "license")
+		templateFuncsMu.Unlock()
+	}()
+
+	content, err := generateWithTemplates(t, map[string]string{
+		"header.tmpl": `{{define "header"}}{{license}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("generateFileContent() error = %v", err)
+	}
+	if !strings.HasPrefix(content, "// SPDX-License-Identifier: MIT") {
+		t.Errorf("registered function was not available:\n%s", content)
+	}
+
+	// Files rendered without the function are not up to date
+	after, err := loadTemplateSet("")
+	if err != nil {
+		t.Fatalf("loadTemplateSet() error = %v", err)
+	}
+	if before.hash == after.hash {
+		t.Error("registering a template function should change the template hash")
+	}
+}
+
+func TestRegisterTemplateFunctionsVersion(t *testing.T) {
+	defer func() {
+		templateFuncsMu.Lock()
+		delete(templateFuncs, "license")
+		delete(templateFuncsVersions, "license")
+		templateFuncsMu.Unlock()
+	}()
+
+	hashWith := func(version string) string {
+		t.Helper()
+		RegisterTemplateFunctionsVersion(version, template.FuncMap{
+			"license": func() string { return "// SPDX-License-Identifier: MIT\n\n" },
+		})
+		set, err := loadTemplateSet("")
+		if err != nil {
+			t.Fatalf("loadTemplateSet() error = %v", err)
+		}
+		return set.hash
+	}
+
+	v1 := hashWith("v1")
+	if again := hashWith("v1"); again != v1 {
+		t.Error("registering the same version should keep the template hash")
+	}
+	if v2 := hashWith("v2"); v2 == v1 {
+		t.Error("registering a new version of a template function should change the template hash")
+	}
+}
+
+func TestLoadTemplateSet(t *testing.T) {
+	builtin, err := loadTemplateSet("")
+	if err != nil {
+		t.Fatalf("loadTemplateSet() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "header.tmpl"), `{{define "header"}}// License{{end}}`)
+	custom, err := loadTemplateSet(dir)
+	if err != nil {
+		t.Fatalf("loadTemplateSet() error = %v", err)
+	}
+	if builtin.hash == custom.hash {
+		t.Error("custom templates should change the template hash")
+	}
+	if len(custom.extras) != 0 {
+		t.Errorf("blocks should not be rendered as extras: %v", custom.extras)
+	}
+
+	if _, err := loadTemplateSet(filepath.Join(dir, "missing")); err == nil {
+		t.Error("loadTemplateSet() expected an error for a missing directory")
+	}
+
+	writeTestFile(t, filepath.Join(dir, "broken.tmpl"), "{{if}")
+	if _, err := loadTemplateSet(dir); err == nil || !strings.Contains(err.Error(), "broken.tmpl") {
+		t.Errorf("loadTemplateSet() error = %v, want the broken file named", err)
+	}
+}
@@ -1,7 +1,11 @@
 package adder
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 )
 
@@ -14,13 +18,18 @@ var Templates = struct {
 	Package: packageTemplate,
 }
 
-const packageTemplate = `// Code generated by adder. DO NOT EDIT.
+const packageTemplate = `{{block "header" .}}{{end -}}
+// Code generated by adder. DO NOT EDIT.
+{{- if .Sources }}
+// Source: {{.Sources}}
+{{- end }}
 
 package {{.Package}}
 
 import (
 	"github.com/jrschumacher/adder"
 	"github.com/spf13/cobra"
+	{{- block "imports" .}}{{end}}
 )
 `
 
@@ -30,6 +39,7 @@ const commandTemplate = `
 {{- $handlerName := .HandlerName }}
 {{- $methodName := .MethodName }}
 {{- $functionName := .FunctionName }}
+{{- $responseName := .ResponseName }}
 
 {{- if $cmd.Arguments}}
 // {{$structName}}Arguments represents the arguments for the {{$cmd.Name}} command
@@ -80,25 +90,186 @@ func (r *{{$structName}}) GetRawArguments() []string {
 // Ensure {{$structName}} implements adder.Request interface at compile time
 var _ adder.Request = (*{{$structName}})(nil)
 
+// Ensure {{$structName}} implements adder.Validator interface at compile time
+var _ adder.Validator = (*{{$structName}})(nil)
+
+// {{$structName}}ValidateFunc is an optional hook for additional checks (e.g. cross-field rules)
+// It is called by Validate after the generated checks pass
+var {{$structName}}ValidateFunc func(req *{{$structName}}) error
+
+// Validate implements the adder.Validator interface
+func (r *{{$structName}}) Validate() error {
+	{{- range $cmd.Arguments}}
+	{{- if and .Required (ne .GetGoType "bool") (ne .GetGoType "int")}}
+	if err := adder.ValidateRequired("{{.Name}}", r.Arguments.{{pascalCase .Name}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- end}}
+
+	{{- range $cmd.Flags}}
+	{{- if and .Required (ne .GetGoType "bool") (ne .GetGoType "int")}}
+	if err := adder.ValidateRequired("{{.Name}}", r.Flags.{{pascalCase .Name}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- if .Enum}}
+	if err := adder.ValidateEnum("{{.Name}}", r.Flags.{{pascalCase .Name}}, []string{{"{"}}{{range $i, $val := .Enum}}{{if $i}}, {{end}}"{{$val}}"{{end}}{{"}"}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- end}}
+
+	{{- range $cmd.PersistentFlags}}
+	{{- if and .Required (ne .GetGoType "bool") (ne .GetGoType "int")}}
+	if err := adder.ValidateRequired("{{.Name}}", r.PersistentFlags.{{pascalCase .Name}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- if .Enum}}
+	if err := adder.ValidateEnum("{{.Name}}", r.PersistentFlags.{{pascalCase .Name}}, []string{{"{"}}{{range $i, $val := .Enum}}{{if $i}}, {{end}}"{{$val}}"{{end}}{{"}"}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- end}}
+
+	{{- range .FlagRules}}
+	if err := adder.{{.Validator}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}adder.FieldSet{Name: "{{$f.Name}}", Set: {{$f.SetExpression}}}{{end}}); err != nil {
+		return err
+	}
+	{{- end}}
+
+	if {{$structName}}ValidateFunc != nil {
+		return {{$structName}}ValidateFunc(r)
+	}
+	return nil
+}
+
+// Default{{$structName}} returns a {{$structName}} populated with the declared flag defaults
+func Default{{$structName}}() *{{$structName}} {
+	return &{{$structName}}{
+		{{- if $cmd.Flags}}
+		Flags: {{$structName}}Flags{
+			{{- range $cmd.Flags}}
+			{{- if ne .Default nil}}
+			{{pascalCase .Name}}: {{.GetDefaultValue}},
+			{{- end}}
+			{{- end}}
+		},
+		{{- end}}
+		{{- if $cmd.PersistentFlags}}
+		PersistentFlags: {{$structName}}PersistentFlags{
+			{{- range $cmd.PersistentFlags}}
+			{{- if ne .Default nil}}
+			{{pascalCase .Name}}: {{.GetDefaultValue}},
+			{{- end}}
+			{{- end}}
+		},
+		{{- end}}
+	}
+}
+
+// {{$structName}}Builder builds {{$structName}} values, e.g. for testing handlers
+type {{$structName}}Builder struct {
+	req {{$structName}}
+}
+
+// New{{$structName}}Builder creates a builder starting from Default{{$structName}}
+func New{{$structName}}Builder() *{{$structName}}Builder {
+	return &{{$structName}}Builder{req: *Default{{$structName}}()}
+}
+{{- range $cmd.Arguments}}
+
+// With{{pascalCase .Name}} sets the {{.Name}} argument
+func (b *{{$structName}}Builder) With{{pascalCase .Name}}(value {{.GetGoType}}) *{{$structName}}Builder {
+	b.req.Arguments.{{pascalCase .Name}} = value
+	return b
+}
+{{- end}}
+{{- range $cmd.Flags}}
+
+// With{{pascalCase .Name}} sets the {{.Name}} flag
+func (b *{{$structName}}Builder) With{{pascalCase .Name}}(value {{.GetGoType}}) *{{$structName}}Builder {
+	b.req.Flags.{{pascalCase .Name}} = value
+	return b
+}
+{{- end}}
+{{- range $cmd.PersistentFlags}}
+
+// With{{pascalCase .Name}} sets the {{.Name}} persistent flag
+func (b *{{$structName}}Builder) With{{pascalCase .Name}}(value {{.GetGoType}}) *{{$structName}}Builder {
+	b.req.PersistentFlags.{{pascalCase .Name}} = value
+	return b
+}
+{{- end}}
+
+// Build returns a copy of the built request
+func (b *{{$structName}}Builder) Build() *{{$structName}} {
+	req := b.req
+	return &req
+}
+
+{{- if $cmd.Output}}
+
+// {{$responseName}} represents the result of the {{$cmd.Name}} command
+type {{$responseName}} struct {
+	{{- range $cmd.Output.Fields}}
+	{{pascalCase .Name}} {{.GetGoType}} ` + "`" + `json:"{{camelCase .Name}}"` + "`" + `{{if .Description}} // {{escapeString .Description}}{{end}}
+	{{- end}}
+}
+{{- end}}
+
 // {{$handlerName}} defines the function type for handling {{$cmd.Name}} commands
+{{- if $cmd.Output}}
+type {{$handlerName}} func(cmd *cobra.Command, req *{{$structName}}) ({{if $cmd.Output.List}}[]{{else}}*{{end}}{{$responseName}}, error)
+{{- else}}
 type {{$handlerName}} func(cmd *cobra.Command, req *{{$structName}}) error
+{{- end}}
+
+{{- if $cmd.Setup}}
+
+// {{pascalCase (cleanCommandName $cmd.Name)}}Setup runs before the {{$cmd.Name}} command and each of its subcommands
+// req holds the persistent flags; the returned teardown, if not nil, runs after the handler
+type {{pascalCase (cleanCommandName $cmd.Name)}}Setup func(cmd *cobra.Command, req *{{$structName}}) (teardown func() error, err error)
+{{- end}}
+
+{{- range $cmd.Errors}}
+
+// {{pascalCase (cleanCommandName $cmd.Name)}}{{pascalCase .Name}}ExitCode is the exit code of {{.Name}} errors of the {{$cmd.Name}} command
+const {{pascalCase (cleanCommandName $cmd.Name)}}{{pascalCase .Name}}ExitCode = {{.Code}}
+
+// New{{pascalCase (cleanCommandName $cmd.Name)}}{{pascalCase .Name}}Error creates a {{.Name}} error that exits with code {{.Code}}
+{{- if .Description}}
+// {{.Description}}
+{{- end}}
+func New{{pascalCase (cleanCommandName $cmd.Name)}}{{pascalCase .Name}}Error(format string, args ...interface{}) error {
+	return adder.Errorf({{pascalCase (cleanCommandName $cmd.Name)}}{{pascalCase .Name}}ExitCode, format, args...)
+}
+{{- end}}
 
 // {{$functionName}} creates a new {{$cmd.Name}} command with the provided handler function
-func {{$functionName}}(handler {{$handlerName}}) *cobra.Command {
+func {{$functionName}}(handler {{$handlerName}}{{if $cmd.Setup}}, setup {{pascalCase (cleanCommandName $cmd.Name)}}Setup{{end}}, opts ...adder.CommandOption) *cobra.Command {
+	options := adder.NewCommandOptions(opts...)
+
 	cmd := &cobra.Command{
 		Use:     "{{cleanCommandName $cmd.Name}}{{range $cmd.Arguments}} [{{.Name}}]{{end}}",
 		{{- if $cmd.Aliases}}
 		Aliases: []string{{"{"}}{{range $i, $alias := $cmd.Aliases}}{{if $i}}, {{end}}"{{$alias}}"{{end}}{{"}"}},
 		{{- end}}
-		Short:   "{{$cmd.Title}}",
-		{{- if $cmd.Arguments}}
-		Args: cobra.ExactArgs({{len $cmd.Arguments}}),
+		Short:   "{{escapeString $cmd.Title}}",
+		{{- if $cmd.Errors}}
+		Long:    {{exitCodesHelp $cmd}},
+		{{- end}}
+		{{- if ge .PromptArgs 0}}
+		Args: options.ExactArgsOrPrompt({{len $cmd.Arguments}}, {{.PromptArgs}}),
+		{{- else if $cmd.Arguments}}
+		Args: adder.ExactArgsOrFromFile({{len $cmd.Arguments}}),
 		{{- end}}
 		{{- if $cmd.Hidden}}
 		Hidden: true,
 		{{- end}}
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd, args, handler)
+			return run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd, args, handler, options)
 		},
 	}
 
@@ -109,9 +280,6 @@ func {{$functionName}}(handler {{$handlerName}}) *cobra.Command {
 	{{- else}}
 	cmd.PersistentFlags().{{.GetCobraFlagMethod}}("{{.Name}}", {{.GetDefaultValue}}, "{{escapeString .Description}}")
 	{{- end}}
-	{{- if .Required}}
-	cmd.MarkPersistentFlagRequired("{{.Name}}")
-	{{- end}}
 	{{- end}}
 
 	// Register flags
@@ -121,20 +289,48 @@ func {{$functionName}}(handler {{$handlerName}}) *cobra.Command {
 	{{- else}}
 	cmd.Flags().{{.GetCobraFlagMethod}}("{{.Name}}", {{.GetDefaultValue}}, "{{escapeString .Description}}")
 	{{- end}}
-	{{- if .Required}}
-	cmd.MarkFlagRequired("{{.Name}}")
+	{{- end}}
+	{{- if .FlagRules}}
+
+	// Register flag rules
+	{{- range .FlagRules}}
+	cmd.{{.CobraMethod}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}"{{$f.Name}}"{{end}})
 	{{- end}}
 	{{- end}}
 
+	{{- if $cmd.Setup}}
+
+	// Run setup before this command and each of its subcommands
+	if setup != nil {
+		adder.RegisterSetup(cmd, func(cmd *cobra.Command) (func() error, error) {
+			req := &{{$structName}}{}
+			{{- range $cmd.PersistentFlags}}
+			req.PersistentFlags.{{pascalCase .Name}}, _ = cmd.Flags().Get{{.GetCobraFlagMethod}}("{{.Name}}")
+			{{- end}}
+			return setup(cmd, req)
+		})
+	}
+	{{- end}}
+
+	// Allow populating the request from a file
+	adder.AddFromFileFlag(cmd)
+	{{- if $cmd.Destructive}}
+
+	// Allow skipping the confirmation prompt
+	adder.AddYesFlag(cmd)
+	{{- end}}
+	{{- if $cmd.Output}}
+
+	// Allow selecting the output format
+	adder.AddOutputFlag(cmd, "{{$cmd.Output.GetDefaultFormat}}")
+	{{- end}}
+	{{- block "constructor" .}}{{end}}
+
 	return cmd
 }
 
 // run{{pascalCase (cleanCommandName $cmd.Name)}} handles argument and flag extraction
-func run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd *cobra.Command, args []string, handler {{$handlerName}}) error {
-	{{- range $i, $arg := $cmd.Arguments}}
-	{{camelCase $arg.Name}} := args[{{$i}}]
-	{{- end}}
-	
+func run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd *cobra.Command, args []string, handler {{$handlerName}}, options *adder.CommandOptions) error {
 	{{- range $cmd.Flags}}
 	{{camelCase .Name}}, _ := cmd.Flags().Get{{.GetCobraFlagMethod}}("{{.Name}}")
 	{{- end}}
@@ -143,33 +339,8 @@ func run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd *cobra.Command, args []s
 	{{camelCase .Name}}, _ := cmd.PersistentFlags().Get{{.GetCobraFlagMethod}}("{{.Name}}")
 	{{- end}}
 
-	{{- range $cmd.Flags}}
-	{{- if .Enum}}
-	// Validate enum for {{.Name}}
-	if err := adder.ValidateEnum("{{.Name}}", {{camelCase .Name}}, []string{{"{"}}{{range $i, $val := .Enum}}{{if $i}}, {{end}}"{{$val}}"{{end}}{{"}"}}); err != nil {
-		return err
-	}
-	{{- end}}
-	{{- end}}
-
-	{{- range $cmd.PersistentFlags}}
-	{{- if .Enum}}
-	// Validate enum for {{.Name}}
-	if err := adder.ValidateEnum("{{.Name}}", {{camelCase .Name}}, []string{{"{"}}{{range $i, $val := .Enum}}{{if $i}}, {{end}}"{{$val}}"{{end}}{{"}"}}); err != nil {
-		return err
-	}
-	{{- end}}
-	{{- end}}
-
 	// Create request
 	req := &{{$structName}}{
-		{{- if $cmd.Arguments}}
-		Arguments: {{$structName}}Arguments{
-			{{- range $cmd.Arguments}}
-			{{pascalCase .Name}}: {{camelCase .Name}},
-			{{- end}}
-		},
-		{{- end}}
 		{{- if $cmd.Flags}}
 		Flags: {{$structName}}Flags{
 			{{- range $cmd.Flags}}
@@ -184,38 +355,177 @@ func run{{pascalCase (cleanCommandName $cmd.Name)}}(cmd *cobra.Command, args []s
 			{{- end}}
 		},
 		{{- end}}
-		RawArguments: args,
 	}
 
-	// Call handler
-	return handler(cmd, req)
+	// Populate request from --from-file, explicit flags and arguments take precedence
+	{{- if or $cmd.Flags $cmd.PersistentFlags}}
+	fromFile, err := adder.LoadRequestFile(cmd, req)
+	if err != nil {
+		return err
+	}
+	if fromFile {
+		{{- range $cmd.Flags}}
+		if cmd.Flags().Changed("{{.Name}}") {
+			req.Flags.{{pascalCase .Name}} = {{camelCase .Name}}
+		}
+		{{- end}}
+		{{- range $cmd.PersistentFlags}}
+		if cmd.PersistentFlags().Changed("{{.Name}}") {
+			req.PersistentFlags.{{pascalCase .Name}} = {{camelCase .Name}}
+		}
+		{{- end}}
+	}
+	{{- else}}
+	if _, err := adder.LoadRequestFile(cmd, req); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- if .RequiredFlags}}
+
+	// Check required flags once the request file is merged, as it may provide them
+	if err := adder.CheckRequiredFlags(cmd{{range .RequiredFlags}}, adder.RequiredFlag{Name: "{{.Name}}", Key: "{{.Key}}"}{{end}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- range $i, $arg := $cmd.Arguments}}
+	if len(args) > {{$i}} {
+		req.Arguments.{{pascalCase $arg.Name}} = args[{{$i}}]
+	}
+	{{- end}}
+	req.RawArguments = args
+	{{- if or .Prompts $cmd.Destructive}}
+
+	// One prompter per execution, so answers typed ahead are kept between prompts
+	prompter := options.PrompterFor(cmd)
+	{{- end}}
+	{{- if .Prompts}}
+
+	// Prompt for missing values when attached to a terminal{{if .RequiredPromptFlags}}, without one required flags must be given{{end}}
+	if prompter != nil {
+		{{- range .Prompts}}
+		if {{.UnsetExpression}}{{if .FlagSet}} && !cmd.{{.FlagSet}}().Changed("{{.Name}}"){{end}} {
+			value, err := prompter.{{.Call}}
+			if err != nil {
+				return err
+			}
+			{{.Field}} = value
+		}
+		{{- end}}
+	}
+	{{- if .RequiredPromptFlags}} else if err := adder.CheckRequiredFlags(cmd{{range .RequiredPromptFlags}}, adder.RequiredFlag{Name: "{{.Name}}", Key: "{{.Key}}"}{{end}}); err != nil {
+		return err
+	}
+	{{- end}}
+	{{- end}}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	{{- if $cmd.Destructive}}
+
+	// Ask for confirmation before running a destructive command
+	if err := adder.Confirm(cmd, prompter, {{printf "%q" $cmd.Confirm}}, req); err != nil {
+		return err
+	}
+	{{- end}}
+
+	{{- if $cmd.Output}}
+
+	// Resolve output format before calling the handler
+	printer, err := adder.CommandPrinter(cmd{{range $cmd.Output.GetColumns}}, adder.Column{Field: "{{camelCase .Name}}", Header: "{{columnHeader .Name}}"}{{end}})
+	if err != nil {
+		return err
+	}
+
+	// Call handler through the middleware chain and render its result
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		result, err := handler(cmd, r.(*{{$structName}}))
+		if err != nil {
+			return err
+		}
+		return printer.Print(cmd.OutOrStdout(), result)
+	})(cmd, req)
+	{{- else}}
+
+	// Call handler through the middleware chain
+	return options.Wrap(cmd, func(cmd *cobra.Command, r adder.Request) error {
+		return handler(cmd, r.(*{{$structName}}))
+	})(cmd, req)
+	{{- end}}
 }
 `
 
+var (
+	templateFuncsMu       sync.RWMutex
+	templateFuncs         = template.FuncMap{}
+	templateFuncsVersions = map[string]string{} // Version of each registered function, see RegisterTemplateFunctionsVersion
+)
+
+// RegisterTemplateFunctions makes functions available to custom templates
+// A function with the name of a built-in one replaces it. Register functions before generating.
+// Only the names of the functions are recorded in .adder.lock, so after changing what a function
+// returns, run adder generate --force or register it with RegisterTemplateFunctionsVersion.
+func RegisterTemplateFunctions(funcs template.FuncMap) {
+	RegisterTemplateFunctionsVersion("", funcs)
+}
+
+// RegisterTemplateFunctionsVersion registers functions like RegisterTemplateFunctions, along with
+// a version of their implementation, e.g. a release or a hash of their source. The version is
+// recorded in .adder.lock, so changing it regenerates the files rendered with the functions.
+func RegisterTemplateFunctionsVersion(version string, funcs template.FuncMap) {
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+
+	for name, fn := range funcs {
+		templateFuncs[name] = fn
+		templateFuncsVersions[name] = version
+	}
+}
+
+// templateFunctionsFingerprint identifies the template functions for the template hash
+// Built-in functions change with adder itself, so its version stands in for them;
+// registered functions add their name and registered version.
+func templateFunctionsFingerprint() string {
+	templateFuncsMu.RLock()
+	defer templateFuncsMu.RUnlock()
+
+	names := make([]string, 0, len(templateFuncs))
+	for name := range templateFuncs {
+		names = append(names, name+"@"+templateFuncsVersions[name])
+	}
+	sort.Strings(names)
+	return generatorVersion() + "\x00" + strings.Join(names, ",")
+}
+
+// fieldName returns the camelCase name of a request field and its json key,
+// avoiding Go reserved keywords
+func fieldName(s string) string {
+	result := camelCase(s)
+	switch result {
+	case "package":
+		return "pkg"
+	case "func":
+		return "fn"
+	case "var":
+		return "variable"
+	case "type":
+		return "typ"
+	case "import":
+		return "imp"
+	case "interface":
+		return "iface"
+	default:
+		return result
+	}
+}
+
 // TemplateFunctions returns the template functions
 func TemplateFunctions() template.FuncMap {
-	return template.FuncMap{
+	funcs := template.FuncMap{
 		"pascalCase": pascalCase,
-		"camelCase": func(s string) string {
-			result := camelCase(s)
-			// Handle Go reserved keywords
-			switch result {
-			case "package":
-				return "pkg"
-			case "func":
-				return "fn"
-			case "var":
-				return "variable"
-			case "type":
-				return "typ"
-			case "import":
-				return "imp"
-			case "interface":
-				return "iface"
-			default:
-				return result
-			}
-		},
+		"camelCase":  fieldName,
 		"cleanCommandName": func(name string) string {
 			// Remove everything from the first space or bracket
 			parts := strings.Fields(name)
@@ -228,6 +538,27 @@ func TemplateFunctions() template.FuncMap {
 			}
 			return name
 		},
+		"columnHeader": func(name string) string {
+			// Table headers are upper case words, e.g. "created-at" -> "CREATED AT"
+			return strings.ToUpper(strings.NewReplacer("-", " ", "_", " ").Replace(name))
+		},
+		"exitCodesHelp": func(cmd *Command) string {
+			// Long help listing the exit codes of a command with declared errors
+			var b strings.Builder
+			b.WriteString(cmd.Title + "\n\nExit Codes:\n")
+			fmt.Fprintf(&b, "  %-3d %s\n", ExitCodeOK, "success")
+			fmt.Fprintf(&b, "  %-3d %s\n", ExitCodeError, "unexpected error")
+			fmt.Fprintf(&b, "  %-3d %s\n", ExitCodeUsage, "invalid usage")
+			fmt.Fprintf(&b, "  %-3d %s\n", ExitCodeValidation, "invalid request")
+			for _, cmdErr := range cmd.Errors {
+				line := cmdErr.Name
+				if cmdErr.Description != "" {
+					line += ": " + cmdErr.Description
+				}
+				fmt.Fprintf(&b, "  %-3d %s\n", cmdErr.Code, line)
+			}
+			return strconv.Quote(strings.TrimSuffix(b.String(), "\n"))
+		},
 		"joinEnum": func(enum []string) string {
 			if len(enum) <= 1 {
 				return strings.Join(enum, "")
@@ -242,4 +573,12 @@ func TemplateFunctions() template.FuncMap {
 			return strings.ReplaceAll(s, `"`, `\"`)
 		},
 	}
+
+	// Registered functions are added last, so they can replace built-in ones
+	templateFuncsMu.RLock()
+	defer templateFuncsMu.RUnlock()
+	for name, fn := range templateFuncs {
+		funcs[name] = fn
+	}
+	return funcs
 }
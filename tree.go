@@ -0,0 +1,160 @@
+package adder
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CommandNode is a parsed command and its position in the command tree
+// The tree follows the input directory layout: hello/greet.md is a subcommand of hello
+type CommandNode struct {
+	Command  *Command
+	Path     []string // Command names from the root, e.g. ["adder", "generate"]
+	Parent   *CommandNode
+	Children []*CommandNode // Sorted by name
+}
+
+// BuildCommandTree arranges parsed commands into a tree below the root command
+// When there is no <binary_name>.md, the root is a command with only a name
+func BuildCommandTree(binaryName string, commands []*Command) *CommandNode {
+	nodes := make(map[string]*CommandNode)
+	var keys []string
+	for _, cmd := range commands {
+		key := commandTreeKey(cmd)
+		if _, exists := nodes[key]; exists {
+			continue
+		}
+		nodes[key] = &CommandNode{Command: cmd}
+		keys = append(keys, key)
+	}
+
+	root, ok := nodes[""]
+	if !ok {
+		root = &CommandNode{Command: &Command{Name: binaryName, IsRootCommand: true}}
+		nodes[""] = root
+	}
+	if binaryName == "" {
+		binaryName = commandName(root.Command.Name)
+	}
+	root.Path = []string{binaryName}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		node := nodes[key]
+
+		// Attach to the nearest ancestor, skipping directories without a command
+		// Sorting the keys puts every parent before its children, so its path is known
+		parentKey := key
+		for {
+			parentKey = path.Dir(parentKey)
+			if parentKey == "." {
+				parentKey = ""
+			}
+			if parent, ok := nodes[parentKey]; ok {
+				node.Parent = parent
+				node.Path = append(append([]string{}, parent.Path...), path.Base(key))
+				parent.Children = append(parent.Children, node)
+				break
+			}
+		}
+	}
+
+	return root
+}
+
+// commandTreeKey returns the slash separated path of a command below the root
+func commandTreeKey(cmd *Command) string {
+	dir := filepath.ToSlash(filepath.Dir(cmd.FilePath))
+	if cmd.IsRootCommand {
+		if cmd.CommandPath == "" {
+			return ""
+		}
+		return dir // index file of a command group
+	}
+	if dir == "." {
+		return commandName(cmd.Name)
+	}
+	return dir + "/" + commandName(cmd.Name)
+}
+
+// commandName removes arguments from a command name (e.g., "hello [name]" -> "hello")
+func commandName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return name
+	}
+	return strings.NewReplacer("[", "", "]", "").Replace(parts[0])
+}
+
+// Name returns the name of the command
+func (n *CommandNode) Name() string {
+	return n.Path[len(n.Path)-1]
+}
+
+// CommandPath returns the full command line of the command, e.g. "adder generate"
+func (n *CommandNode) CommandPath() string {
+	return strings.Join(n.Path, " ")
+}
+
+// Usage returns the usage line, with <required> and [optional] arguments
+func (n *CommandNode) Usage() string {
+	parts := []string{n.CommandPath()}
+	if len(n.Children) > 0 {
+		parts = append(parts, "[command]")
+	}
+	for _, arg := range n.Command.Arguments {
+		if arg.Required {
+			parts = append(parts, "<"+arg.Name+">")
+		} else {
+			parts = append(parts, "["+arg.Name+"]")
+		}
+	}
+	if len(n.Command.Flags) > 0 || len(n.Command.PersistentFlags) > 0 || len(n.InheritedFlags()) > 0 {
+		parts = append(parts, "[flags]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// InheritedFlags returns the persistent flags of the command's ancestors, nearest first
+func (n *CommandNode) InheritedFlags() []Flag {
+	var flags []Flag
+	for p := n.Parent; p != nil; p = p.Parent {
+		flags = append(flags, p.Command.PersistentFlags...)
+	}
+	return flags
+}
+
+// Walk calls fn for the node and all of its descendants, parents before children
+func (n *CommandNode) Walk(fn func(*CommandNode) error) error {
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, child := range n.Children {
+		if err := child.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatFlagDefault returns the default value of a flag for documentation, or "" if it has none
+func formatFlagDefault(f Flag) string {
+	switch value := f.Default.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, v := range value {
+			values[i] = fmt.Sprint(v)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,50 @@
+package adder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCommandTree(t *testing.T) {
+	commands := []*Command{
+		{Name: "app", FilePath: "app.md", IsRootCommand: true, PersistentFlags: []Flag{{Name: "verbose", Type: TypeBool}}},
+		{Name: "serve", FilePath: "serve.md"},
+		{Name: "user", FilePath: "user/user.md", IsRootCommand: true, CommandPath: "user", PersistentFlags: []Flag{{Name: "org", Type: TypeString}}},
+		{Name: "create [name]", FilePath: "user/create.md", Arguments: []Argument{{Name: "name", Required: true}}, Flags: []Flag{{Name: "admin", Type: TypeBool}}},
+		{Name: "hello", FilePath: "hello.md"},
+		{Name: "greet [name]", FilePath: "hello/greet.md"},
+		{Name: "orphan", FilePath: "missing/orphan.md"},
+	}
+
+	root := BuildCommandTree("app", commands)
+
+	var paths []string
+	_ = root.Walk(func(n *CommandNode) error {
+		paths = append(paths, n.CommandPath())
+		return nil
+	})
+	want := []string{"app", "app hello", "app hello greet", "app orphan", "app serve", "app user", "app user create"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Walk() paths = %v, want %v", paths, want)
+	}
+
+	create := root.Children[3].Children[0]
+	if got := create.Usage(); got != "app user create <name> [flags]" {
+		t.Errorf("Usage() = %q", got)
+	}
+	inherited := create.InheritedFlags()
+	if len(inherited) != 2 || inherited[0].Name != "org" || inherited[1].Name != "verbose" {
+		t.Errorf("InheritedFlags() = %+v, want org then verbose", inherited)
+	}
+}
+
+func TestBuildCommandTree_WithoutRootFile(t *testing.T) {
+	root := BuildCommandTree("app", []*Command{{Name: "serve", FilePath: "serve.md"}})
+
+	if root.Name() != "app" || len(root.Children) != 1 {
+		t.Fatalf("root = %q with %d children, want app with 1", root.Name(), len(root.Children))
+	}
+	if got := root.Usage(); got != "app [command]" {
+		t.Errorf("Usage() = %q", got)
+	}
+}
@@ -18,6 +18,7 @@ const (
 
 // Config represents the generator configuration
 type Config struct {
+	Name                string            `yaml:"name,omitempty"` // Name of a target, set on the entries of Targets
 	BinaryName          string            `yaml:"binary_name"`
 	InputDir            string            `yaml:"input"`
 	OutputDir           string            `yaml:"output"`
@@ -25,7 +26,12 @@ type Config struct {
 	GeneratedFileSuffix string            `yaml:"generated_file_suffix"`
 	IndexFormat         string            `yaml:"index_format,omitempty"`
 	PackageStrategy     string            `yaml:"package_strategy,omitempty"` // "single", "directory", "path"
+	TemplatesDir        string            `yaml:"templates,omitempty"`        // Directory of *.tmpl files overriding or extending the built-in templates
 	Validation          ValidationConfig  `yaml:"validation,omitempty"`
+	Targets             []*Config         `yaml:"targets,omitempty"` // CLIs generated from one config file; the settings above are their defaults
+
+	sources  map[string]string // Where values came from, keyed by config key; see Source
+	warnings []string          // Deprecated keys of the config file; see Warnings
 }
 
 // ValidationConfig represents validation-specific settings
@@ -48,17 +54,22 @@ func DefaultConfig() *Config {
 
 // Command represents a command definition from markdown
 type Command struct {
-	Title           string     `yaml:"title"`
-	Name            string     `yaml:"name"`
-	Aliases         []string   `yaml:"aliases"`
-	Hidden          bool       `yaml:"hidden"`
-	Arguments       []Argument `yaml:"arguments"`
-	Flags           []Flag     `yaml:"flags"`
-	PersistentFlags []Flag     `yaml:"persistent_flags"`
-	Description     string     // Markdown content
-	FilePath        string     // Source file path
-	IsRootCommand   bool       // True if this is a root command for subcommands
-	CommandPath     string     // The command path (e.g., "example" for "example" root command)
+	Title           string         `yaml:"title"`
+	Name            string         `yaml:"name"`
+	Aliases         []string       `yaml:"aliases"`
+	Hidden          bool           `yaml:"hidden"`
+	Setup           bool           `yaml:"setup"` // Generate a setup hook for the command and its subcommands
+	Destructive     bool           `yaml:"destructive"` // Ask for confirmation unless --yes is given
+	Confirm         string         `yaml:"confirm"`     // Confirmation message template, implies destructive
+	Arguments       []Argument     `yaml:"arguments"`
+	Flags           []Flag         `yaml:"flags"`
+	PersistentFlags []Flag         `yaml:"persistent_flags"`
+	Output          *Output        `yaml:"output"`
+	Errors          []CommandError `yaml:"errors"`
+	Description     string         // Markdown content
+	FilePath        string         // Source file path
+	IsRootCommand   bool           // True if this is a root command for subcommands
+	CommandPath     string         // The command path (e.g., "example" for "example" root command)
 }
 
 // Argument represents a command argument
@@ -67,6 +78,11 @@ type Argument struct {
 	Description string `yaml:"description"`
 	Required    bool   `yaml:"required"`
 	Type        string `yaml:"type"`
+
+	// Prompting for missing values on a terminal
+	Prompt      bool   `yaml:"-"`         // Set by prompt: true or prompt: "Label"
+	PromptLabel string `yaml:"-"`         // Label given with prompt: "Label"
+	Sensitive   bool   `yaml:"sensitive"` // Read with hidden input
 }
 
 // Flag represents a command flag
@@ -78,6 +94,79 @@ type Flag struct {
 	Default     interface{} `yaml:"default"`
 	Required    bool        `yaml:"required"`
 	Enum        []string    `yaml:"enum"`
+
+	// Flag rules reference other flags of the same command by name
+	MutuallyExclusive []string `yaml:"mutually_exclusive"`
+	RequiredTogether  []string `yaml:"required_together"`
+	OneRequired       []string `yaml:"one_required"`
+
+	// Prompting for missing values on a terminal
+	Prompt      bool   `yaml:"-"`         // Set by prompt: true or prompt: "Label"
+	PromptLabel string `yaml:"-"`         // Label given with prompt: "Label"
+	Sensitive   bool   `yaml:"sensitive"` // Read with hidden input
+}
+
+// CommandError represents a declared error of a command and its exit code
+type CommandError struct {
+	Name        string `yaml:"name"`
+	Code        int    `yaml:"code"`
+	Description string `yaml:"description"`
+}
+
+// Output represents the declared result of a command
+type Output struct {
+	Format  string        `yaml:"format"`  // Default output format (json, yaml, table, ndjson)
+	List    bool          `yaml:"list"`    // Handler returns a list of results
+	Fields  []OutputField `yaml:"fields"`  // Fields of the result
+	Columns []string      `yaml:"columns"` // Table columns, defaults to all fields
+}
+
+// OutputField represents a field of a command result
+type OutputField struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+}
+
+// GetGoType returns the Go type for the output field
+func (f *OutputField) GetGoType() string {
+	switch f.Type {
+	case TypeBool:
+		return TypeBool
+	case TypeInt:
+		return TypeInt
+	case TypeString:
+		return TypeString
+	case TypeStringArray:
+		return "[]string"
+	default:
+		return TypeString
+	}
+}
+
+// GetDefaultFormat returns the default output format
+func (o *Output) GetDefaultFormat() string {
+	if o.Format == "" {
+		return "table"
+	}
+	return o.Format
+}
+
+// GetColumns returns the fields rendered as table columns, in column order
+func (o *Output) GetColumns() []OutputField {
+	if len(o.Columns) == 0 {
+		return o.Fields
+	}
+
+	var columns []OutputField
+	for _, name := range o.Columns {
+		for _, field := range o.Fields {
+			if field.Name == name {
+				columns = append(columns, field)
+			}
+		}
+	}
+	return columns
 }
 
 // GetGoType returns the Go type for the flag
@@ -173,6 +262,28 @@ func (f *Flag) GetDefaultValue() string {
 	}
 }
 
+// GetIsSetExpression returns a Go boolean expression reporting whether the
+// given field reference holds a value other than the flag default
+func (f *Flag) GetIsSetExpression(field string) string {
+	if f.Type == TypeStringArray {
+		return fmt.Sprintf("len(%s) > 0", field)
+	}
+	return fmt.Sprintf("%s != %s", field, f.GetDefaultValue())
+}
+
+// GetPromptLabel returns the label used when prompting for the flag
+func (f *Flag) GetPromptLabel() string {
+	return promptLabel(f.PromptLabel, f.Description, f.Name)
+}
+
+// GetIsUnsetExpression returns a Go expression reporting whether field holds the flag's default
+func (f *Flag) GetIsUnsetExpression(field string) string {
+	if f.Type == TypeStringArray {
+		return fmt.Sprintf("len(%s) == 0", field)
+	}
+	return fmt.Sprintf("%s == %s", field, f.GetDefaultValue())
+}
+
 // GetValidationTag returns the validation tag for the field
 func (f *Flag) GetValidationTag() string {
 	var tags []string
@@ -207,6 +318,22 @@ func (a *Argument) GetValidationTag() string {
 	return ""
 }
 
+// GetPromptLabel returns the label used when prompting for the argument
+func (a *Argument) GetPromptLabel() string {
+	return promptLabel(a.PromptLabel, a.Description, a.Name)
+}
+
+// promptLabel picks the explicit label, then the description, then the name
+func promptLabel(label, description, name string) string {
+	if label != "" {
+		return label
+	}
+	if description != "" {
+		return description
+	}
+	return name
+}
+
 // GetGoType returns the Go type for the argument
 func (a *Argument) GetGoType() string {
 	switch a.Type {
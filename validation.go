@@ -11,8 +11,29 @@ type Request interface {
 	GetRawArguments() []string
 }
 
+// Validator is implemented by requests that can check their own constraints
+// Generated requests validate requiredness, enums and flag rules in Validate
+type Validator interface {
+	Validate() error
+}
+
+// ValidateRequest validates a request if it implements the Validator interface
+func ValidateRequest(req Request) error {
+	if v, ok := req.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// FieldSet describes whether a named request field holds a non-default value
+// It is used by the generated flag rule checks
+type FieldSet struct {
+	Name string
+	Set  bool
+}
+
 // ValidateEnum validates that a value is in the allowed enum list
-// Returns nil if valid, error with helpful message if invalid
+// Returns nil if valid, a *ValidationError with helpful message if invalid
 func ValidateEnum(flagName, value string, validValues []string) error {
 	for _, validValue := range validValues {
 		if value == validValue {
@@ -20,14 +41,80 @@ func ValidateEnum(flagName, value string, validValues []string) error {
 		}
 	}
 
-	var enumList string
-	if len(validValues) <= 1 {
-		enumList = strings.Join(validValues, "")
-	} else if len(validValues) == 2 {
-		enumList = validValues[0] + " or " + validValues[1]
-	} else {
-		enumList = strings.Join(validValues[:len(validValues)-1], ", ") + ", or " + validValues[len(validValues)-1]
+	return NewValidationError(fmt.Sprintf("invalid %s: %s (must be %s)", flagName, value, joinEnumValues(validValues, "or")), flagName)
+}
+
+// ValidateRequired validates that a required string or string array value is not empty
+// Other types cannot be distinguished from their zero value and are always accepted
+func ValidateRequired(name string, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return nil
+		}
+	case []string:
+		if len(v) > 0 {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return NewValidationError("missing required "+name, name)
+}
+
+// ValidateMutuallyExclusive validates that at most one of the fields is set
+func ValidateMutuallyExclusive(fields ...FieldSet) error {
+	var set []string
+	for _, field := range fields {
+		if field.Set {
+			set = append(set, field.Name)
+		}
+	}
+
+	if len(set) > 1 {
+		return NewValidationError(joinEnumValues(set, "and")+" cannot be used together", set...)
+	}
+	return nil
+}
+
+// ValidateRequiredTogether validates that the fields are either all set or all unset
+func ValidateRequiredTogether(fields ...FieldSet) error {
+	var set, unset []string
+	for _, field := range fields {
+		if field.Set {
+			set = append(set, field.Name)
+		} else {
+			unset = append(unset, field.Name)
+		}
+	}
+
+	if len(set) > 0 && len(unset) > 0 {
+		return NewValidationError(fmt.Sprintf("%s must be used together with %s", joinEnumValues(set, "and"), joinEnumValues(unset, "and")), unset...)
+	}
+	return nil
+}
+
+// ValidateOneRequired validates that at least one of the fields is set
+func ValidateOneRequired(fields ...FieldSet) error {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.Set {
+			return nil
+		}
+		names = append(names, field.Name)
 	}
 
-	return fmt.Errorf("invalid %s: %s (must be %s)", flagName, value, enumList)
-}
\ No newline at end of file
+	return NewValidationError("at least one of "+joinEnumValues(names, "or")+" is required", names...)
+}
+
+// joinEnumValues joins values into a readable list (e.g. "a, b, or c")
+func joinEnumValues(values []string, conjunction string) string {
+	if len(values) <= 1 {
+		return strings.Join(values, "")
+	}
+	if len(values) == 2 {
+		return values[0] + " " + conjunction + " " + values[1]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + ", " + conjunction + " " + values[len(values)-1]
+}
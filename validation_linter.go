@@ -2,9 +2,17 @@ package adder
 
 import (
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
+	"text/template"
 )
 
+// reservedFlagNames lists flag names registered by generated commands and why
+var reservedFlagNames = map[string]string{
+	FromFileFlag: "used to populate requests from a file",
+}
+
 // validateFlagConfiguration validates a flag's configuration for consistency
 func validateFlagConfiguration(flag *Flag, filePath string, index int) error {
 	// Validate required fields
@@ -67,6 +75,11 @@ func validateFlagConfiguration(flag *Flag, filePath string, index int) error {
 		}
 	}
 
+	// Validate hidden input is only used for free text
+	if flag.Sensitive && ((flag.Type != "string" && flag.Type != "") || len(flag.Enum) > 0) {
+		return fmt.Errorf("file %s: flag %s: sensitive is only supported on string flags without enum", filePath, flag.Name)
+	}
+
 	return nil
 }
 
@@ -95,6 +108,11 @@ func validateArgumentConfiguration(arg *Argument, filePath string, index int) er
 		return fmt.Errorf("file %s: argument %s: invalid type '%s' (must be one of: string, int, bool)", filePath, arg.Name, arg.Type)
 	}
 
+	// Validate prompting is only used for string arguments
+	if (arg.Prompt || arg.Sensitive) && arg.Type != "string" {
+		return fmt.Errorf("file %s: argument %s: prompt and sensitive are only supported on string arguments", filePath, arg.Name)
+	}
+
 	return nil
 }
 
@@ -210,5 +228,237 @@ func validateCommandConfiguration(cmd *Command, filePath string) error {
 		fieldNames[flag.Name] = true
 	}
 
+	// Validate no flag uses a name reserved for generated flags
+	for _, flags := range [][]Flag{cmd.Flags, cmd.PersistentFlags} {
+		for _, flag := range flags {
+			if reason, reserved := reservedFlagNames[flag.Name]; reserved {
+				return fmt.Errorf("file %s: flag %s: name is reserved (%s)", filePath, flag.Name, reason)
+			}
+		}
+	}
+
+	// Validate declared output
+	if cmd.Output != nil {
+		if err := validateOutputConfiguration(cmd, filePath); err != nil {
+			return err
+		}
+	}
+
+	// Validate confirmation of destructive commands
+	if cmd.Destructive {
+		if err := validateConfirmation(cmd, filePath); err != nil {
+			return err
+		}
+	}
+
+	// Validate declared errors
+	if err := validateCommandErrors(cmd, filePath); err != nil {
+		return err
+	}
+
+	// Validate flag rules reference declared flags
+	flagNames := make(map[string]bool)
+	for _, flag := range cmd.Flags {
+		flagNames[flag.Name] = true
+	}
+	for _, flag := range cmd.PersistentFlags {
+		flagNames[flag.Name] = true
+	}
+
+	for _, flags := range [][]Flag{cmd.Flags, cmd.PersistentFlags} {
+		for _, flag := range flags {
+			if err := validateFlagRules(&flag, flagNames, filePath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFlagRules validates that a flag's rules only reference other declared flags
+func validateFlagRules(flag *Flag, flagNames map[string]bool, filePath string) error {
+	rules := map[string][]string{
+		"mutually_exclusive": flag.MutuallyExclusive,
+		"required_together":  flag.RequiredTogether,
+		"one_required":       flag.OneRequired,
+	}
+
+	for _, rule := range []string{"mutually_exclusive", "required_together", "one_required"} {
+		for _, name := range rules[rule] {
+			if name == flag.Name {
+				return fmt.Errorf("file %s: flag %s: %s cannot reference the flag itself", filePath, flag.Name, rule)
+			}
+			if !flagNames[name] {
+				return fmt.Errorf("file %s: flag %s: %s references unknown flag '%s'", filePath, flag.Name, rule, name)
+			}
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// validateOutputConfiguration validates a command's declared output
+func validateOutputConfiguration(cmd *Command, filePath string) error {
+	output := cmd.Output
+
+	if len(output.Fields) == 0 {
+		return fmt.Errorf("file %s: output: at least one field is required", filePath)
+	}
+
+	if output.Format != "" {
+		isValidFormat := false
+		for _, format := range OutputFormats {
+			if output.Format == format {
+				isValidFormat = true
+				break
+			}
+		}
+		if !isValidFormat {
+			return fmt.Errorf("file %s: output: invalid format '%s' (must be one of: %s)", filePath, output.Format, strings.Join(OutputFormats, ", "))
+		}
+	}
+
+	fieldNames := make(map[string]bool)
+	declared := make(map[string]bool)
+	for i, field := range output.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("file %s: output field %d: name is required", filePath, i)
+		}
+		if fieldNames[pascalCase(field.Name)] {
+			return fmt.Errorf("file %s: output: duplicate field name '%s'", filePath, field.Name)
+		}
+		fieldNames[pascalCase(field.Name)] = true
+		declared[field.Name] = true
+
+		validTypes := []string{"string", "bool", "int", "stringArray"}
+		isValidType := false
+		for _, validType := range validTypes {
+			if field.Type == validType {
+				isValidType = true
+				break
+			}
+		}
+		if !isValidType {
+			return fmt.Errorf("file %s: output field %s: invalid type '%s' (must be one of: string, bool, int, stringArray)", filePath, field.Name, field.Type)
+		}
+	}
+
+	for _, column := range output.Columns {
+		if !declared[column] {
+			return fmt.Errorf("file %s: output: column '%s' is not a declared field", filePath, column)
+		}
+	}
+
+	// The generated --output flag must not clash with declared flags
+	for _, flags := range [][]Flag{cmd.Flags, cmd.PersistentFlags} {
+		for _, flag := range flags {
+			if flag.Name == OutputFlag || flag.Shorthand == "o" {
+				return fmt.Errorf("file %s: flag %s: conflicts with the generated --output/-o flag for commands with a declared output", filePath, flag.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sampleRequest returns a value shaped like the generated request of a command, with zero values
+func sampleRequest(cmd *Command) map[string]interface{} {
+	zero := func(goType string) interface{} {
+		switch goType {
+		case TypeBool:
+			return false
+		case TypeInt:
+			return 0
+		case "[]string":
+			return []string{}
+		default:
+			return ""
+		}
+	}
+
+	req := map[string]interface{}{"RawArguments": []string{}}
+	if len(cmd.Arguments) > 0 {
+		arguments := make(map[string]interface{})
+		for _, arg := range cmd.Arguments {
+			arguments[pascalCase(arg.Name)] = zero(arg.GetGoType())
+		}
+		req["Arguments"] = arguments
+	}
+	for name, flags := range map[string][]Flag{"Flags": cmd.Flags, "PersistentFlags": cmd.PersistentFlags} {
+		if len(flags) == 0 {
+			continue
+		}
+		fields := make(map[string]interface{})
+		for _, flag := range flags {
+			fields[pascalCase(flag.Name)] = zero(flag.GetGoType())
+		}
+		req[name] = fields
+	}
+	return req
+}
+
+// validateCommandTree validates the generated flags of each command against the persistent flags
+// of its ancestors; cobra panics when an inherited flag and a command's flag share a shorthand
+func validateCommandTree(root *CommandNode) error {
+	return root.Walk(func(node *CommandNode) error {
+		cmd := node.Command
+		for _, flag := range node.InheritedFlags() {
+			if cmd.Destructive && (flag.Name == YesFlag || flag.Shorthand == "y") {
+				return fmt.Errorf("file %s: inherited flag %s: conflicts with the generated --yes/-y flag for destructive commands", cmd.FilePath, flag.Name)
+			}
+			if cmd.Output != nil && (flag.Name == OutputFlag || flag.Shorthand == "o") {
+				return fmt.Errorf("file %s: inherited flag %s: conflicts with the generated --output/-o flag for commands with a declared output", cmd.FilePath, flag.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// validateCommandErrors validates a command's declared errors
+func validateCommandErrors(cmd *Command, filePath string) error {
+	names := make(map[string]bool)
+	codes := make(map[int]string)
+	for i, cmdErr := range cmd.Errors {
+		if cmdErr.Name == "" {
+			return fmt.Errorf("file %s: error %d: name is required", filePath, i)
+		}
+		if names[pascalCase(cmdErr.Name)] {
+			return fmt.Errorf("file %s: duplicate error name '%s'", filePath, cmdErr.Name)
+		}
+		names[pascalCase(cmdErr.Name)] = true
+
+		if cmdErr.Code <= ExitCodeValidation || cmdErr.Code > MaxExitCode {
+			return fmt.Errorf("file %s: error %s: code %d is out of range (must be %d-%d, lower codes are reserved)", filePath, cmdErr.Name, cmdErr.Code, ExitCodeValidation+1, MaxExitCode)
+		}
+		if other, exists := codes[cmdErr.Code]; exists {
+			return fmt.Errorf("file %s: error %s: code %d is already used by '%s'", filePath, cmdErr.Name, cmdErr.Code, other)
+		}
+		codes[cmdErr.Code] = cmdErr.Name
+	}
+
+	return nil
+}
+
+// validateConfirmation validates the confirmation message and the generated --yes flag
+func validateConfirmation(cmd *Command, filePath string) error {
+	tmpl, err := template.New("confirm").Option("missingkey=error").Parse(cmd.Confirm)
+	if err != nil {
+		return fmt.Errorf("file %s: confirm: invalid template: %w", filePath, err)
+	}
+
+	// Render it as Confirm would, so unknown fields fail here instead of just before the command runs
+	if err := tmpl.Execute(io.Discard, sampleRequest(cmd)); err != nil {
+		return fmt.Errorf("file %s: confirm: invalid template: %w", filePath, err)
+	}
+
+	for _, flags := range [][]Flag{cmd.Flags, cmd.PersistentFlags} {
+		for _, flag := range flags {
+			if flag.Name == YesFlag || flag.Shorthand == "y" {
+				return fmt.Errorf("file %s: flag %s: conflicts with the generated --yes/-y flag for destructive commands", filePath, flag.Name)
+			}
+		}
+	}
+
+	return nil
+}
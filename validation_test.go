@@ -0,0 +1,86 @@
+package adder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "non-empty string", value: "value", wantErr: false},
+		{name: "empty string", value: "", wantErr: true},
+		{name: "non-empty string array", value: []string{"a"}, wantErr: false},
+		{name: "empty string array", value: []string(nil), wantErr: true},
+		{name: "zero int is accepted", value: 0, wantErr: false},
+		{name: "false bool is accepted", value: false, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequired("field", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRequired() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFlagRules(t *testing.T) {
+	set := func(name string) FieldSet { return FieldSet{Name: name, Set: true} }
+	unset := func(name string) FieldSet { return FieldSet{Name: name} }
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr string
+	}{
+		{name: "mutually exclusive with one set", err: ValidateMutuallyExclusive(set("json"), unset("yaml"))},
+		{name: "mutually exclusive with both set", err: ValidateMutuallyExclusive(set("json"), set("yaml")), wantErr: "json and yaml cannot be used together"},
+		{name: "required together all set", err: ValidateRequiredTogether(set("user"), set("password"))},
+		{name: "required together none set", err: ValidateRequiredTogether(unset("user"), unset("password"))},
+		{name: "required together partially set", err: ValidateRequiredTogether(set("user"), unset("password")), wantErr: "user must be used together with password"},
+		{name: "one required with one set", err: ValidateOneRequired(unset("file"), set("url"))},
+		{name: "one required with none set", err: ValidateOneRequired(unset("file"), unset("url"), unset("stdin")), wantErr: "at least one of file, url, or stdin is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == "" {
+				if tt.err != nil {
+					t.Errorf("unexpected error: %v", tt.err)
+				}
+				return
+			}
+			if tt.err == nil || !strings.Contains(tt.err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want to contain %q", tt.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type validatingRequest struct {
+	err error
+}
+
+func (r *validatingRequest) GetRawArguments() []string { return nil }
+func (r *validatingRequest) Validate() error           { return r.err }
+
+type plainRequest struct{}
+
+func (r *plainRequest) GetRawArguments() []string { return nil }
+
+func TestValidateRequest(t *testing.T) {
+	if err := ValidateRequest(&plainRequest{}); err != nil {
+		t.Errorf("ValidateRequest() on non-validator returned %v", err)
+	}
+	if err := ValidateRequest(&validatingRequest{}); err != nil {
+		t.Errorf("ValidateRequest() on valid request returned %v", err)
+	}
+	if err := ValidateRequest(&validatingRequest{err: ValidateRequired("name", "")}); err == nil {
+		t.Errorf("ValidateRequest() expected error from Validate")
+	}
+}